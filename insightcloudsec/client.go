@@ -0,0 +1,476 @@
+package insightcloudsec
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ics "github.com/gstotts/insightcloudsec"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const systemVersionPath = "/v2/public/system/version"
+
+// apiClient is passed to every resource and data source.  It embeds the
+// InsightCloudSec client so its services remain available as c.Clouds, c.Users,
+// etc., and carries what the provider learned about the instance during configure.
+type apiClient struct {
+	*ics.Client
+
+	baseURL       string
+	apiKey        string
+	httpClient    *http.Client
+	serverVersion *version.Version
+	readOnly      bool
+}
+
+// withContext returns a copy of the client whose requests, including those made
+// through the ics services, are bound to ctx.  This lets resource timeouts and
+// Terraform's cancellation stop in-flight HTTP calls.
+func (c *apiClient) withContext(ctx context.Context) (*apiClient, error) {
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient := &http.Client{Transport: &contextTransport{ctx: ctx, base: base}}
+
+	client, err := ics.NewClient(&ics.Config{
+		BaseURL:    c.baseURL,
+		ApiKey:     c.apiKey,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bound := *c
+	bound.Client = client
+	bound.httpClient = httpClient
+	return &bound, nil
+}
+
+// contextTransport replaces the context of each request with the one the
+// client was bound to.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// readOnlyError is returned in place of any change while the provider is in read
+// only mode, so that plans can be run with credentials that must never mutate anything.
+func readOnlyError(resource, action string) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Provider is Read Only",
+		Detail: fmt.Sprintf("%s\n%s",
+			fmt.Sprintf("%s was not %s because the provider is configured with read_only = true (INSIGHTCLOUDSEC_READ_ONLY).", resource, action),
+			"Data sources and refreshes continue to work.  Unset read_only to apply changes."),
+	}}
+}
+
+// apiError is returned by request when the API responds with an error status
+type apiError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("[ERROR] InsightCloudSec returned %s: %s", e.Status, e.Body)
+}
+
+// expired reports whether the error_message of a JSON error body says the
+// credentials have expired
+func (e *apiError) expired() bool {
+	var body struct {
+		Message string `json:"error_message"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &body); err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(body.Message), "expired")
+}
+
+// request calls an API endpoint directly, for those the ics client does not
+// wrap.  The body is sent as JSON and the response is decoded into out if given.
+func (c *apiClient) request(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Api-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &apiError{StatusCode: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(data))}
+	}
+
+	if out != nil && len(data) > 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// detectServerVersion doubles as a credential check, since the version endpoint
+// requires an authenticated session.  Instances without the endpoint only get a
+// warning.
+func (c *apiClient) detectServerVersion(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var result struct {
+		Version string `json:"version"`
+	}
+
+	err := c.request(ctx, http.MethodGet, systemVersionPath, nil, &result)
+	if err != nil {
+		var apiErr *apiError
+		switch {
+		case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed):
+			// Older instances do not have the endpoint, so their version is unknown
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Unable to Detect InsightCloudSec Version",
+				Detail: fmt.Sprintf("%s\n%s",
+					fmt.Sprintf("%s returned %s, so the provider assumes the instance supports every feature.", systemVersionPath, apiErr.Status),
+					"The credentials will be checked by the first request that needs them."),
+			})
+			return diags
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized && apiErr.expired():
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Expired InsightCloudSec Credentials",
+				Detail: fmt.Sprintf("%s\n\n%s\n%s",
+					"The API key or session used by the provider has expired.  Generate a new key for the account and update apikey (INSIGHTCLOUDSEC_API_KEY).",
+					"Error from API:", err),
+			})
+		case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Invalid InsightCloudSec Credentials",
+				Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
+					"InsightCloudSec rejected the credentials supplied to the provider.",
+					"Check that apikey (or username and password) belong to an active account on this instance.",
+					"Error from API:", err),
+			})
+		case errors.As(err, &apiErr):
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unexpected Response from InsightCloudSec",
+				Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
+					"The provider was unable to verify its connection to InsightCloudSec.",
+					"Check that url points at the base of the InsightCloudSec instance.",
+					"Error from API:", err),
+			})
+		default:
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to Reach InsightCloudSec",
+				Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
+					fmt.Sprintf("The provider could not connect to %s.", c.baseURL),
+					"Check the url, proxy and TLS settings and that the instance is reachable from this host.",
+					"Error:", err),
+			})
+		}
+		return diags
+	}
+
+	v, err := version.NewVersion(result.Version)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Unable to parse InsightCloudSec version %q: %s", result.Version, err))
+		return diags
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Connected to InsightCloudSec version %s", v))
+	c.serverVersion = v
+	return diags
+}
+
+// supportsVersion reports whether the instance is at least the given version.
+// Instances whose version could not be detected are assumed to be current.
+func (c *apiClient) supportsVersion(minimum string) bool {
+	if c.serverVersion == nil {
+		return true
+	}
+	return c.serverVersion.GreaterThanOrEqual(version.Must(version.NewVersion(minimum)))
+}
+
+// newHTTPTransport builds the base transport for API calls from the provider's
+// TLS and proxy settings.
+func newHTTPTransport(d *schema.ResourceData) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+	}
+
+	caPEM := []byte(d.Get("ca_cert_pem").(string))
+	if path := d.Get("ca_cert_file").(string); path != "" {
+		var err error
+		caPEM, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] Unable to read ca_cert_file: %s", err)
+		}
+	}
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("[ERROR] No valid PEM encoded certificates were found in the provided CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cert := d.Get("client_cert").(string); cert != "" {
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(d.Get("client_key").(string)))
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] Unable to load client_cert and client_key: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	if proxy := d.Get("proxy_url").(string); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] Invalid proxy_url: %s", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// retryTransport wraps every request made by the InsightCloudSec client, retrying
+// throttled (429) and server side (5xx) failures with exponential backoff and
+// spacing requests out when a rate limit is configured.  Requests that are not
+// idempotent, such as those adding clouds, are only retried when throttled or
+// when they failed before reaching the server, so a request the server has
+// already acted on is never repeated.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
+	limiter    *rateLimiter
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int, waitMin, waitMax time.Duration, perSecond float64) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if waitMax < waitMin {
+		waitMax = waitMin
+	}
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		waitMin:    waitMin,
+		waitMax:    waitMax,
+		limiter:    newRateLimiter(perSecond),
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// Buffer the body so that it can be replayed on each attempt
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		r := req.Clone(ctx)
+		if body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+
+		var connected bool
+		if !idempotent(req.Method) {
+			r = r.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+				GotConn: func(httptrace.GotConnInfo) { connected = true },
+			}))
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if !shouldRetry(ctx, req.Method, connected, resp, err) || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Request to %s failed, retrying in %s: %s", req.URL.Path, wait, err))
+		} else {
+			tflog.Warn(ctx, fmt.Sprintf("Request to %s returned %d, retrying in %s", req.URL.Path, resp.StatusCode, wait))
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the time to wait before the next attempt, preferring the
+// Retry-After header of the response when the API provides one.  Neither waits
+// longer than waitMax.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > t.waitMax {
+				wait = t.waitMax
+			}
+			return wait
+		}
+	}
+
+	wait := time.Duration(float64(t.waitMin) * math.Pow(2, float64(attempt)))
+	if wait > t.waitMax || wait < t.waitMin {
+		wait = t.waitMax
+	}
+	return wait
+}
+
+// shouldRetry reports whether a request can safely be sent again.  connected is
+// only tracked for requests that are not idempotent.
+func shouldRetry(ctx context.Context, method string, connected bool, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return idempotent(method) || !connected
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent(method) && resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter handles both the delay-seconds and HTTP-date forms of the header
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// rateLimiter spaces requests evenly so no more than the configured number are
+// sent per second.  A nil limiter never blocks.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
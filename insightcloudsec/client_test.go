@@ -0,0 +1,411 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestRetryTransport_RetriesThrottledRequests(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"test"}` {
+			t.Errorf("expected request body to be replayed, got %q", body)
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newRetryTransport(nil, 3, time.Millisecond, 10*time.Millisecond, 0)}
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{"name":"test"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newRetryTransport(nil, 2, time.Millisecond, time.Millisecond, 0)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newRetryTransport(nil, 3, time.Millisecond, time.Millisecond, 0)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryTransport_NonIdempotentRequests(t *testing.T) {
+	cases := map[string]struct {
+		method   string
+		status   int
+		expected int32
+	}{
+		"post throttled":     {http.MethodPost, http.StatusTooManyRequests, 3},
+		"post server error":  {http.MethodPost, http.StatusBadGateway, 1},
+		"put server error":   {http.MethodPut, http.StatusBadGateway, 3},
+		"delete unavailable": {http.MethodDelete, http.StatusServiceUnavailable, 3},
+	}
+
+	for name, tc := range cases {
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(tc.status)
+		}))
+
+		client := &http.Client{Transport: newRetryTransport(nil, 2, time.Millisecond, time.Millisecond, 0)}
+		req, _ := http.NewRequest(tc.method, srv.URL, strings.NewReader(`{}`))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		resp.Body.Close()
+		srv.Close()
+
+		if calls != tc.expected {
+			t.Errorf("%s: expected %d calls, got %d", name, tc.expected, calls)
+		}
+	}
+}
+
+func TestShouldRetry_ConnectionErrors(t *testing.T) {
+	ctx := context.Background()
+	err := errors.New("connection reset")
+
+	cases := map[string]struct {
+		method    string
+		connected bool
+		expected  bool
+	}{
+		"post before connecting": {http.MethodPost, false, true},
+		"post after connecting":  {http.MethodPost, true, false},
+		"get after connecting":   {http.MethodGet, true, true},
+	}
+
+	for name, tc := range cases {
+		if got := shouldRetry(ctx, tc.method, tc.connected, nil, err); got != tc.expected {
+			t.Errorf("%s: expected %t, got %t", name, tc.expected, got)
+		}
+	}
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	rt := newRetryTransport(nil, 5, time.Second, 5*time.Second, 0)
+
+	cases := []struct {
+		attempt    int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{0, "", time.Second},
+		{1, "", 2 * time.Second},
+		{2, "", 4 * time.Second},
+		{3, "", 5 * time.Second},
+		{30, "", 5 * time.Second},
+		{0, "3", 3 * time.Second},
+		{0, "120", 5 * time.Second},
+	}
+
+	for _, tc := range cases {
+		resp := &http.Response{Header: http.Header{}}
+		if tc.retryAfter != "" {
+			resp.Header.Set("Retry-After", tc.retryAfter)
+		}
+		if got := rt.backoff(tc.attempt, resp); got != tc.expected {
+			t.Errorf("attempt %d with Retry-After %q: expected %s, got %s", tc.attempt, tc.retryAfter, tc.expected, got)
+		}
+	}
+}
+
+func TestRateLimiter_SpacesRequests(t *testing.T) {
+	l := newRateLimiter(50)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("expected requests to be spaced out, took %s", elapsed)
+	}
+}
+
+func testTLSTransport(t *testing.T, raw map[string]interface{}) (*http.Transport, error) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+	return newHTTPTransport(d)
+}
+
+func TestHTTPTransport_CustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	cases := map[string]struct {
+		raw     map[string]interface{}
+		success bool
+	}{
+		"system roots only": {map[string]interface{}{}, false},
+		"ca_cert_pem":       {map[string]interface{}{"ca_cert_pem": string(caPEM)}, true},
+		"insecure":          {map[string]interface{}{"insecure_skip_verify": true}, true},
+	}
+
+	for name, tc := range cases {
+		transport, err := testTLSTransport(t, tc.raw)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+
+		resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if (err == nil) != tc.success {
+			t.Errorf("%s: expected success to be %t, got err: %v", name, tc.success, err)
+		}
+	}
+}
+
+func TestHTTPTransport_CAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	transport, err := testTLSTransport(t, map[string]interface{}{"ca_cert_file": path})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+}
+
+func TestHTTPTransport_InvalidSettings(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"bad ca bundle":  {"ca_cert_pem": "not a certificate"},
+		"missing file":   {"ca_cert_file": filepath.Join(t.TempDir(), "missing.pem")},
+		"bad client key": {"client_cert": "not a certificate", "client_key": "not a key"},
+	}
+
+	for name, raw := range cases {
+		if _, err := testTLSTransport(t, raw); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func testConfigureProvider(t *testing.T, url string) (*apiClient, diag.Diagnostics) {
+	raw := map[string]interface{}{
+		"url":         url,
+		"apikey":      "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxy",
+		"max_retries": 0,
+	}
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw))
+	if c, ok := p.Meta().(*apiClient); ok {
+		return c, diags
+	}
+	return nil, diags
+}
+
+func TestProviderConfigure_DetectsServerVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != systemVersionPath || r.Header.Get("Api-Key") == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"version": "23.7.11"}`))
+	}))
+	defer srv.Close()
+
+	c, diags := testConfigureProvider(t, srv.URL)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if c.serverVersion.String() != "23.7.11" {
+		t.Fatalf("expected version 23.7.11, got %s", c.serverVersion)
+	}
+	if !c.supportsVersion("23.1.0") || c.supportsVersion("24.1.0") {
+		t.Fatalf("unexpected version gating for %s", c.serverVersion)
+	}
+}
+
+func TestProviderConfigure_Diagnostics(t *testing.T) {
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable.Close()
+
+	cases := map[string]struct {
+		status  int
+		body    string
+		summary string
+	}{
+		"bad key":                    {http.StatusUnauthorized, `{"error_message": "Invalid API Key"}`, "Invalid InsightCloudSec Credentials"},
+		"expired key":                {http.StatusUnauthorized, `{"error_message": "API Key has expired"}`, "Expired InsightCloudSec Credentials"},
+		"bad key mentioning expired": {http.StatusUnauthorized, `Invalid key, the previous key expired`, "Invalid InsightCloudSec Credentials"},
+		"server error":               {http.StatusInternalServerError, `{"error_message": "Token expired while harvesting"}`, "Unexpected Response from InsightCloudSec"},
+	}
+
+	for name, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			w.Write([]byte(tc.body))
+		}))
+
+		_, diags := testConfigureProvider(t, srv.URL)
+		if !diags.HasError() || diags[0].Summary != tc.summary {
+			t.Errorf("%s: expected %q, got %v", name, tc.summary, diags)
+		}
+		srv.Close()
+	}
+
+	for _, status := range []int{http.StatusNotFound, http.StatusMethodNotAllowed} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		c, diags := testConfigureProvider(t, srv.URL)
+		if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+			t.Errorf("%d: expected a single warning, got %v", status, diags)
+		}
+		if c == nil || c.serverVersion != nil || !c.supportsVersion("99.0") {
+			t.Errorf("%d: expected an unknown version", status)
+		}
+		srv.Close()
+	}
+
+	_, diags := testConfigureProvider(t, unreachable.URL)
+	if !diags.HasError() || diags[0].Summary != "Unable to Reach InsightCloudSec" {
+		t.Errorf("unreachable: expected connection error, got %v", diags)
+	}
+}
+
+func TestReadOnly_RefusesChanges(t *testing.T) {
+	c := &apiClient{readOnly: true, httpClient: http.DefaultClient}
+	resources := map[string]*schema.Resource{
+		"insightcloudsec_cloud":          resourceCloud(),
+		"insightcloudsec_custom_insight": resourceInsight(),
+		"insightcloudsec_user":           resourceUser(),
+	}
+
+	for name, r := range resources {
+		d := r.TestResourceData()
+		d.SetId("1")
+
+		for action, fn := range map[string]func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics{
+			"create": r.CreateContext,
+			"update": r.UpdateContext,
+			"delete": r.DeleteContext,
+		} {
+			diags := fn(context.Background(), d, c)
+			if !diags.HasError() || diags[0].Summary != "Provider is Read Only" {
+				t.Errorf("%s %s: expected read only error, got %v", name, action, diags)
+			}
+		}
+	}
+}
+
+func TestWithContext_CancelsInFlightRequests(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := &apiClient{baseURL: srv.URL, httpClient: &http.Client{Transport: newRetryTransport(nil, 0, 0, 0, 0)}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	bound, err := c.withContext(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Requests built without a context, as the ics services do, must still be cancelled
+	err = bound.request(context.Background(), http.MethodGet, "/", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline to be exceeded, got %v", err)
+	}
+}
+
+func TestProviderConfigure_RetryWaitBounds(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":            "https://example.invalid",
+		"apikey":         "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxy",
+		"retry_wait_min": 10,
+		"retry_wait_max": 5,
+	}
+
+	diags := Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(raw))
+	if !diags.HasError() || diags[0].Summary != "Invalid Retry Settings" {
+		t.Errorf("expected invalid retry settings, got %v", diags)
+	}
+}
@@ -3,7 +3,9 @@ package insightcloudsec
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 
 	ics "github.com/gstotts/insightcloudsec"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -11,19 +13,26 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// The arguments a single cloud can be looked up by
+var CLOUD_LOOKUP_ATTRS = []string{"name", "id", "account_id", "resource_id"}
+
 func datasSourceCloud() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceCloudRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the cloud to retrieve",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: CLOUD_LOOKUP_ATTRS,
+				Description:  "The name of the cloud to retrieve",
 			},
 			"id": {
-				Type:        schema.TypeInt,
-				Computed:    true,
-				Description: "The ID associated the the data source for this cloud",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: CLOUD_LOOKUP_ATTRS,
+				Description:  "The ID of the cloud to retrieve",
 			},
 			"cloud_type": {
 				Type:        schema.TypeString,
@@ -31,14 +40,18 @@ func datasSourceCloud() *schema.Resource {
 				Description: "The identifier for the type of cloud utilized.  Examples:  AWS, AZURE_ARM, etc.",
 			},
 			"account_id": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The identifier for the account associated with the cloud.  In the case of AWS, this is the account ID.  In Azure, this is the subscription ID",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: CLOUD_LOOKUP_ATTRS,
+				Description:  "The identifier for the account associated with the cloud.  In the case of AWS, this is the account ID.  In Azure, this is the subscription ID",
 			},
 			"resource_id": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The resource ID provided by the console for the cloud",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: CLOUD_LOOKUP_ATTRS,
+				Description:  "The resource ID provided by the console for the cloud",
 			},
 			"strategy_id": {
 				Type:        schema.TypeInt,
@@ -55,22 +68,102 @@ func datasSourceCloud() *schema.Resource {
 				Computed:    true,
 				Description: "The group resource ID for the cloud",
 			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the cloud.  Examples:  DEFAULT, PAUSED, ERROR, etc.",
+			},
+			"creation_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the cloud was added to InsightCloudSec",
+			},
+			"last_refreshed": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the cloud was last harvested",
+			},
+			"resource_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of resources harvested from the cloud",
+			},
+			"failed_resource_types": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of resource types that failed to harvest",
+			},
+			"badges": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The badges of the cloud",
+			},
+			"regions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The regions of the cloud that are harvested",
+			},
+			"strategy_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the harvesting strategy for the cloud",
+			},
 		},
 	}
 }
 
 func dataSourceCloudRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	var diags diag.Diagnostics
-	cloud_name := d.Get("name").(string)
 
-	cloud, err := c.Clouds.GetByName(cloud_name)
+	var cloud ics.Cloud
+	if v, ok := d.GetOk("name"); ok {
+		cloud, err = c.Clouds.GetByName(v.(string))
+	} else if v, ok := d.GetOk("id"); ok {
+		cloud, err = findCloud(c, "id", strconv.Itoa(v.(int)))
+	} else if v, ok := d.GetOk("account_id"); ok {
+		cloud, err = findCloud(c, "account", v.(string))
+	} else {
+		cloud, err = findCloud(c, "resource_id", d.Get("resource_id").(string))
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	tflog.Debug(ctx, fmt.Sprintf("Cloud Returned from API: \n%v\n", cloud))
 
+	// The badges, regions and strategy are extras, so a cloud is still returned
+	// when they cannot be read, for example with a role that cannot list them
+	badges, err := listResourceBadges(ctx, c, cloud.ResourceID)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Unable to read the badges of cloud %s: %s", cloud.ResourceID, err))
+	}
+
+	enabled := make([]string, 0)
+	regions, err := listCloudRegions(ctx, c, cloud.ResourceID)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Unable to read the regions of cloud %s: %s", cloud.ResourceID, err))
+	}
+	for _, region := range regions {
+		if !strings.EqualFold(region.Status, REGION_DISABLED) {
+			enabled = append(enabled, region.Name)
+		}
+	}
+
+	var strategy harvestingStrategy
+	if cloud.StrategyID != 0 {
+		err = c.request(ctx, http.MethodGet, fmt.Sprintf(harvestingStrategyPath, strconv.Itoa(cloud.StrategyID)), nil, &strategy)
+		if err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Unable to read harvesting strategy %d of cloud %s: %s", cloud.StrategyID, cloud.ResourceID, err))
+			strategy = harvestingStrategy{}
+		}
+	}
+
 	d.Set("name", cloud.Name)
 	d.Set("cloud_type", cloud.CloudTypeID)
 	d.Set("account_id", cloud.AccountID)
@@ -78,7 +171,14 @@ func dataSourceCloudRead(ctx context.Context, d *schema.ResourceData, m interfac
 	d.Set("strategy_id", cloud.StrategyID)
 	d.Set("cloud_organization_id", cloud.CloudOrgID)
 	d.Set("group_resource_id", cloud.GroupResourceID)
-	d.Set("resource_id", cloud.ResourceID)
+	d.Set("status", cloud.Status)
+	d.Set("creation_time", cloud.Created)
+	d.Set("last_refreshed", cloud.LastRefreshed)
+	d.Set("resource_count", cloud.ResourceCount)
+	d.Set("failed_resource_types", cloud.FailedResourceTypes)
+	d.Set("badges", flattenBadgeMap(badges[cloud.ResourceID]))
+	d.Set("regions", enabled)
+	d.Set("strategy_name", strategy.Name)
 
 	d.SetId(strconv.Itoa(cloud.ID))
 
@@ -1,10 +1,19 @@
 package insightcloudsec
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
+	ics "github.com/gstotts/insightcloudsec"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccInsightCloudSec_DataSource_Cloud(t *testing.T) {
@@ -21,6 +30,14 @@ func TestAccInsightCloudSec_DataSource_Cloud(t *testing.T) {
 					testDataSourceID("Cloud", name),
 				),
 			},
+			{
+				Config: testAccInsightCloudSec_DataSource_CloudByResourceIDConfig(rnd),
+				Check: resource.ComposeTestCheckFunc(
+					testDataSourceID("Cloud", name),
+					resource.TestCheckResourceAttr(name, "name", "Test Cloud"),
+					resource.TestCheckResourceAttrSet(name, "status"),
+				),
+			},
 		},
 	})
 }
@@ -31,3 +48,110 @@ data "insightcloudsec_cloud" "%[1]s" {
 	name = "Test Cloud"
 }`, dataSourceName)
 }
+
+func testAccInsightCloudSec_DataSource_CloudByResourceIDConfig(dataSourceName string) string {
+	return fmt.Sprintf(`
+data "insightcloudsec_cloud" "by_name" {
+	name = "Test Cloud"
+}
+
+data "insightcloudsec_cloud" "%[1]s" {
+	resource_id = data.insightcloudsec_cloud.by_name.resource_id
+}`, dataSourceName)
+}
+
+func TestDataSourceCloudLookupArguments(t *testing.T) {
+	cases := map[string]struct {
+		raw   map[string]interface{}
+		valid bool
+	}{
+		"name":        {map[string]interface{}{"name": "Production"}, true},
+		"id":          {map[string]interface{}{"id": 1}, true},
+		"account id":  {map[string]interface{}{"account_id": "123456789012"}, true},
+		"resource id": {map[string]interface{}{"resource_id": "divvyorganizationservice:1"}, true},
+		"none":        {map[string]interface{}{}, false},
+		"two":         {map[string]interface{}{"name": "Production", "id": 1}, false},
+	}
+
+	for name, tc := range cases {
+		diags := datasSourceCloud().Validate(terraform.NewResourceConfigRaw(tc.raw))
+		if diags.HasError() == tc.valid {
+			t.Errorf("%s: expected valid to be %t, got %v", name, tc.valid, diags)
+		}
+	}
+}
+
+func TestDataSourceCloudRead(t *testing.T) {
+	clouds := []ics.Cloud{
+		{ID: 1, Name: "Production", CloudTypeID: "AWS", AccountID: "123456789012", ResourceID: "divvyorganizationservice:1", StrategyID: 5},
+		{ID: 2, Name: "Development", CloudTypeID: "AWS", AccountID: "210987654321", ResourceID: "divvyorganizationservice:2"},
+	}
+
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		extras   bool
+		id       string
+		badges   map[string]interface{}
+		regions  []interface{}
+		strategy string
+		errorMsg string
+	}{
+		"id":                 {raw: map[string]interface{}{"id": 1}, extras: true, id: "1", badges: map[string]interface{}{"env": "prod"}, regions: []interface{}{"us-east-1"}, strategy: "Daily"},
+		"account id":         {raw: map[string]interface{}{"account_id": "210987654321"}, extras: true, id: "2", badges: map[string]interface{}{}, regions: []interface{}{"us-east-1"}},
+		"resource id":        {raw: map[string]interface{}{"resource_id": "divvyorganizationservice:1"}, extras: true, id: "1", badges: map[string]interface{}{"env": "prod"}, regions: []interface{}{"us-east-1"}, strategy: "Daily"},
+		"extras unavailable": {raw: map[string]interface{}{"id": 1}, id: "1", badges: map[string]interface{}{}, regions: []interface{}{}},
+		"unknown id":         {raw: map[string]interface{}{"id": 99}, errorMsg: "No cloud found with id"},
+		"unknown account":    {raw: map[string]interface{}{"account_id": "000000000000"}, errorMsg: "No cloud found with account"},
+	}
+
+	for name, tc := range cases {
+		extras := tc.extras
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/clouds/list"):
+				json.NewEncoder(w).Encode(ics.CloudList{Clouds: clouds})
+			case !extras:
+				w.WriteHeader(http.StatusForbidden)
+			case r.URL.Path == cloudBadgesListPath:
+				json.NewEncoder(w).Encode(map[string]interface{}{"resources": []resourceBadges{
+					{ResourceID: "divvyorganizationservice:1", Badges: []cloudBadge{{Key: "env", Value: "prod"}}},
+				}})
+			case strings.HasSuffix(r.URL.Path, "/regions/list"):
+				json.NewEncoder(w).Encode(map[string]interface{}{"regions": []cloudRegion{
+					{Name: "us-east-1", Status: "ENABLED"},
+					{Name: "eu-west-1", Status: "DISABLED"},
+				}})
+			case r.URL.Path == fmt.Sprintf(harvestingStrategyPath, "5"):
+				json.NewEncoder(w).Encode(harvestingStrategy{ID: 5, Name: "Daily"})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		c := &apiClient{
+			baseURL:    srv.URL,
+			apiKey:     "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxy",
+			httpClient: http.DefaultClient,
+		}
+		d := schema.TestResourceDataRaw(t, datasSourceCloud().Schema, tc.raw)
+		diags := dataSourceCloudRead(context.Background(), d, c)
+		srv.Close()
+
+		if tc.errorMsg != "" {
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.errorMsg) {
+				t.Errorf("%s: expected an error containing %q, got %v", name, tc.errorMsg, diags)
+			}
+			continue
+		}
+		if diags.HasError() {
+			t.Errorf("%s: unexpected diagnostics: %v", name, diags)
+			continue
+		}
+		if d.Id() != tc.id {
+			t.Errorf("%s: expected cloud %s, got %s", name, tc.id, d.Id())
+		}
+		if !reflect.DeepEqual(d.Get("badges"), tc.badges) || !reflect.DeepEqual(d.Get("regions"), tc.regions) || d.Get("strategy_name") != tc.strategy {
+			t.Errorf("%s: unexpected badges %v, regions %v or strategy %q", name, d.Get("badges"), d.Get("regions"), d.Get("strategy_name"))
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"strconv"
 	"time"
 
-	ics "github.com/gstotts/insightcloudsec"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -42,7 +41,10 @@ func dataSourceCloudTypes() *schema.Resource {
 }
 
 func dataSourceCloudTypesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	var diags diag.Diagnostics
 	ctypes, _ := c.Clouds.ListTypes()
@@ -0,0 +1,228 @@
+package insightcloudsec
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	ics "github.com/gstotts/insightcloudsec"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// cloudFilter holds the optional filters of the clouds data source.  Empty
+// fields match every cloud.
+type cloudFilter struct {
+	CloudType       string
+	Status          string
+	NameRegex       *regexp.Regexp
+	AccountIDPrefix string
+	Badges          []cloudBadge
+}
+
+func dataSourceClouds() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCloudsRead,
+		Schema: map[string]*schema.Schema{
+			"cloud_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return clouds of this type.  Examples:  AWS, AZURE_ARM, etc.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return clouds with this status.  Examples:  DEFAULT, PAUSED, ERROR, etc.",
+			},
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsValidRegExp),
+				Description:      "Only return clouds whose name matches this regular expression",
+			},
+			"account_id_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return clouds whose account ID starts with this prefix",
+			},
+			"badges": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only return clouds with all of these badges",
+			},
+			"clouds": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The ID of the cloud",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the cloud",
+						},
+						"cloud_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The identifier for the type of cloud utilized.  Examples:  AWS, AZURE_ARM, etc.",
+						},
+						"account_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The identifier for the account associated with the cloud",
+						},
+						"resource_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The resource ID provided by the console for the cloud",
+						},
+						"strategy_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The harvesting strategy ID for the cloud",
+						},
+						"cloud_organization_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The organization ID for the cloud",
+						},
+						"group_resource_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The group resource ID for the cloud",
+						},
+						"creation_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "When the cloud was added to InsightCloudSec",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the cloud",
+						},
+					},
+				},
+			},
+			"total_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The total count of clouds returned",
+			},
+		},
+	}
+}
+
+func dataSourceCloudsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	filter := cloudFilter{
+		CloudType:       d.Get("cloud_type").(string),
+		Status:          d.Get("status").(string),
+		AccountIDPrefix: d.Get("account_id_prefix").(string),
+		Badges:          expandBadgeMap(d.Get("badges").(map[string]interface{})),
+	}
+	if v, ok := d.GetOk("name_regex"); ok {
+		filter.NameRegex = regexp.MustCompile(v.(string))
+	}
+
+	clouds, err := c.Clouds.List()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Clouds Returned from API: %d", len(clouds.Clouds)))
+
+	// Badges are only fetched when filtering on them, as the list does not include them
+	var badges map[string][]cloudBadge
+	if len(filter.Badges) > 0 {
+		var resourceIDs []string
+		for _, cloud := range clouds.Clouds {
+			resourceIDs = append(resourceIDs, cloud.ResourceID)
+		}
+		badges, err = listResourceBadges(ctx, c, resourceIDs...)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	cloudDetails := make([]interface{}, 0)
+	for _, cloud := range filterClouds(clouds.Clouds, badges, filter) {
+		cloudDetails = append(cloudDetails, map[string]interface{}{
+			"id":                    cloud.ID,
+			"name":                  cloud.Name,
+			"cloud_type":            cloud.CloudTypeID,
+			"account_id":            cloud.AccountID,
+			"resource_id":           cloud.ResourceID,
+			"strategy_id":           cloud.StrategyID,
+			"cloud_organization_id": cloud.CloudOrgID,
+			"group_resource_id":     cloud.GroupResourceID,
+			"creation_time":         cloud.Created,
+			"status":                cloud.Status,
+		})
+	}
+
+	if err := d.Set("clouds", cloudDetails); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("total_count", len(cloudDetails))
+
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	return diags
+}
+
+// filterClouds returns the clouds matching every filter, using badges keyed by
+// the cloud resource ID
+func filterClouds(clouds []ics.Cloud, badges map[string][]cloudBadge, filter cloudFilter) []ics.Cloud {
+	var matches []ics.Cloud
+	for _, cloud := range clouds {
+		if filter.CloudType != "" && !strings.EqualFold(cloud.CloudTypeID, filter.CloudType) {
+			continue
+		}
+		if filter.Status != "" && !strings.EqualFold(cloud.Status, filter.Status) {
+			continue
+		}
+		if filter.NameRegex != nil && !filter.NameRegex.MatchString(cloud.Name) {
+			continue
+		}
+		if !strings.HasPrefix(cloud.AccountID, filter.AccountIDPrefix) {
+			continue
+		}
+		if !hasBadges(badges[cloud.ResourceID], filter.Badges) {
+			continue
+		}
+		matches = append(matches, cloud)
+	}
+	return matches
+}
+
+// hasBadges reports whether every wanted badge is among the badges
+func hasBadges(badges, want []cloudBadge) bool {
+	for _, w := range want {
+		found := false
+		for _, badge := range badges {
+			if badge == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package insightcloudsec
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	ics "github.com/gstotts/insightcloudsec"
+)
+
+func TestFilterClouds(t *testing.T) {
+	clouds := []ics.Cloud{
+		{ID: 1, Name: "prod-us", CloudTypeID: "AWS", AccountID: "123456789012", ResourceID: "divvyorganizationservice:1", Status: "DEFAULT"},
+		{ID: 2, Name: "dev-us", CloudTypeID: "AWS", AccountID: "210987654321", ResourceID: "divvyorganizationservice:2", Status: "PAUSED"},
+		{ID: 3, Name: "prod-eu", CloudTypeID: "AZURE_ARM", AccountID: "1234-abcd", ResourceID: "divvyorganizationservice:3", Status: "DEFAULT"},
+	}
+	badges := map[string][]cloudBadge{
+		"divvyorganizationservice:1": {{Key: "env", Value: "prod"}, {Key: "team", Value: "core"}},
+		"divvyorganizationservice:3": {{Key: "env", Value: "prod"}},
+	}
+
+	cases := map[string]struct {
+		filter cloudFilter
+		want   []int
+	}{
+		"no filters":     {cloudFilter{}, []int{1, 2, 3}},
+		"cloud type":     {cloudFilter{CloudType: "aws"}, []int{1, 2}},
+		"status":         {cloudFilter{Status: "PAUSED"}, []int{2}},
+		"name regex":     {cloudFilter{NameRegex: regexp.MustCompile("^prod-")}, []int{1, 3}},
+		"account prefix": {cloudFilter{AccountIDPrefix: "1234"}, []int{1, 3}},
+		"badges":         {cloudFilter{Badges: []cloudBadge{{Key: "env", Value: "prod"}, {Key: "team", Value: "core"}}}, []int{1}},
+		"combined":       {cloudFilter{CloudType: "AWS", Badges: []cloudBadge{{Key: "env", Value: "prod"}}}, []int{1}},
+	}
+
+	for name, tc := range cases {
+		var got []int
+		for _, cloud := range filterClouds(clouds, badges, tc.filter) {
+			got = append(got, cloud.ID)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: expected clouds %v, got %v", name, tc.want, got)
+		}
+	}
+}
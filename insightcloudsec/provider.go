@@ -2,7 +2,11 @@ package insightcloudsec
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"regexp"
+	"strings"
+	"time"
 
 	ics "github.com/gstotts/insightcloudsec"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -22,20 +26,116 @@ func Provider() *schema.Provider {
 			},
 			"apikey": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Sensitive:    true,
 				DefaultFunc:  schema.EnvDefaultFunc("INSIGHTCLOUDSEC_API_KEY", nil),
-				Description:  "ApiKey for use with InsightCloudSec API calls",
+				Description:  "ApiKey for use with InsightCloudSec API calls.  Conflicts with username and password",
 				ValidateFunc: validation.StringMatch(regexp.MustCompile("[A-Za-z0-9-_]{51}"), "API keys must only contain charachters a-z, A-Z, 0-9, hyphens and underscores"),
 			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("INSIGHTCLOUDSEC_USERNAME", nil),
+				Description: "Username to log in to InsightCloudSec with when not using an API key",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("INSIGHTCLOUDSEC_PASSWORD", nil),
+				Description: "Password to log in to InsightCloudSec with when not using an API key",
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("INSIGHTCLOUDSEC_CA_CERT_FILE", nil),
+				ConflictsWith: []string{"ca_cert_pem"},
+				Description:   "Path to a PEM encoded CA bundle used to verify the InsightCloudSec server certificate",
+			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_file"},
+				Description:   "PEM encoded CA bundle used to verify the InsightCloudSec server certificate",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("INSIGHTCLOUDSEC_INSECURE_SKIP_VERIFY", false),
+				Description: "Skip verification of the InsightCloudSec server certificate.  Not recommended outside of testing",
+			},
+			"client_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_key"},
+				Description:  "PEM encoded client certificate for mutual TLS authentication",
+			},
+			"client_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"client_cert"},
+				Description:  "PEM encoded private key for client_cert",
+			},
+			"proxy_url": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INSIGHTCLOUDSEC_PROXY_URL", nil),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https", "socks5"})),
+				Description:      "URL of an HTTP proxy to send requests through.  Defaults to the HTTPS_PROXY and HTTP_PROXY environment variables",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("INSIGHTCLOUDSEC_READ_ONLY", false),
+				Description: "Refuse to create, update or delete resources.  Data sources and refreshes continue to work",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("INSIGHTCLOUDSEC_MAX_RETRIES", 3),
+				Description:  "The number of times to retry a request that was throttled or failed with a server error",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_wait_min": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("INSIGHTCLOUDSEC_RETRY_WAIT_MIN", 1),
+				Description:  "The minimum time in seconds to wait between retries",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_wait_max": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("INSIGHTCLOUDSEC_RETRY_WAIT_MAX", 30),
+				Description:  "The maximum time in seconds to wait between retries, including when the API provides a Retry-After header",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"rate_limit_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("INSIGHTCLOUDSEC_RATE_LIMIT_PER_SECOND", 0),
+				Description:  "The maximum number of requests to send to the API per second.  Defaults to 0 (no limit)",
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"insightcloudsec_cloud":          resourceCloud(),
-			"insightcloudsec_custom_insight": resourceInsight(),
-			"insightcloudsec_user":           resourceUser(),
+			"insightcloudsec_cloud":               resourceCloud(),
+			"insightcloudsec_cloud_badge":         resourceCloudBadge(),
+			"insightcloudsec_cloud_badges":        resourceCloudBadges(),
+			"insightcloudsec_cloud_organization":  resourceCloudOrganization(),
+			"insightcloudsec_cloud_regions":       resourceCloudRegions(),
+			"insightcloudsec_edh_configuration":   resourceEDHConfiguration(),
+			"insightcloudsec_kubernetes_cluster":  resourceKubernetesCluster(),
+			"insightcloudsec_custom_insight":      resourceInsight(),
+			"insightcloudsec_group":               resourceGroup(),
+			"insightcloudsec_group_membership":    resourceGroupMembership(),
+			"insightcloudsec_harvesting_strategy": resourceHarvestingStrategy(),
+			"insightcloudsec_user":                resourceUser(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"insightcloudsec_cloud":       datasSourceCloud(),
+			"insightcloudsec_clouds":      dataSourceClouds(),
 			"insightcloudsec_cloud_types": dataSourceCloudTypes(),
 			"insightcloudsec_users":       dataSourceUsers(),
 		},
@@ -46,21 +146,111 @@ func Provider() *schema.Provider {
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	url := d.Get("url").(string)
 	apiKey := d.Get("apikey").(string)
+	username := d.Get("username").(string)
+	password := d.Get("password").(string)
 
 	var diags diag.Diagnostics
 
-	if (url != "") && (apiKey != "") {
+	if apiKey != "" && (username != "" || password != "") {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Conflicting Authentication Methods",
+			Detail: fmt.Sprintf("%s\n%s",
+				"Both an API key and a username/password were provided to the InsightCloudSec provider.",
+				"Set only one of apikey (INSIGHTCLOUDSEC_API_KEY) or username and password (INSIGHTCLOUDSEC_USERNAME and INSIGHTCLOUDSEC_PASSWORD)."),
+		})
+		return nil, diags
+	}
+
+	if (username != "") != (password != "") {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Incomplete Credentials",
+			Detail:   "Both username (INSIGHTCLOUDSEC_USERNAME) and password (INSIGHTCLOUDSEC_PASSWORD) must be set to log in to InsightCloudSec.",
+		})
+		return nil, diags
+	}
+
+	if d.Get("retry_wait_min").(int) > d.Get("retry_wait_max").(int) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid Retry Settings",
+			Detail:   "retry_wait_min (INSIGHTCLOUDSEC_RETRY_WAIT_MIN) must not be greater than retry_wait_max (INSIGHTCLOUDSEC_RETRY_WAIT_MAX).",
+		})
+		return nil, diags
+	}
+
+	if (url != "") && (apiKey != "" || username != "") {
+		base, err := newHTTPTransport(d)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Invalid TLS or Proxy Settings",
+				Detail:   err.Error(),
+			})
+			return nil, diags
+		}
+
+		var transport http.RoundTripper = newRetryTransport(
+			base,
+			d.Get("max_retries").(int),
+			time.Duration(d.Get("retry_wait_min").(int))*time.Second,
+			time.Duration(d.Get("retry_wait_max").(int))*time.Second,
+			d.Get("rate_limit_per_second").(float64),
+		)
+
+		if username != "" {
+			s := newSession(url, username, password, transport)
+			if err := s.login(ctx); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Error Logging In to InsightCloudSec",
+					Detail:   err.Error(),
+				})
+				return nil, diags
+			}
+			registerSession(s)
+			transport = s
+		}
+
 		config := ics.Config{
-			BaseURL: url,
-			ApiKey:  apiKey,
+			BaseURL:    url,
+			ApiKey:     apiKey,
+			HTTPClient: &http.Client{Transport: transport},
 		}
 
 		c, err := ics.NewClient(&config)
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
-		return c, diags
+
+		client := &apiClient{
+			Client:     c,
+			baseURL:    strings.TrimSuffix(url, "/"),
+			apiKey:     apiKey,
+			httpClient: config.HTTPClient,
+			readOnly:   d.Get("read_only").(bool),
+		}
+
+		diags = append(diags, client.detectServerVersion(ctx)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		return client, diags
 	}
 
-	return &ics.Client{}, diags
+	if url == "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Missing InsightCloudSec URL",
+			Detail:   "The url argument (or INSIGHTCLOUDSEC_BASE_URL) must be set to the base URL of the InsightCloudSec instance.",
+		})
+	} else {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Missing InsightCloudSec Credentials",
+			Detail:   "Either apikey (INSIGHTCLOUDSEC_API_KEY) or username and password (INSIGHTCLOUDSEC_USERNAME and INSIGHTCLOUDSEC_PASSWORD) must be set.",
+		})
+	}
+	return nil, diags
 }
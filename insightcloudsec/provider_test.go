@@ -49,8 +49,8 @@ func testPreCheckBaseUrl(t *testing.T) {
 }
 
 func testPreCheckApiKey(t *testing.T) {
-	if v := os.Getenv("INSIGHTCLOUDSEC_API_KEY"); v == "" {
-		t.Fatal("INSIGHTCLOUDSEC_API_KEY must be set for acceptance testing")
+	if os.Getenv("INSIGHTCLOUDSEC_API_KEY") == "" && os.Getenv("INSIGHTCLOUDSEC_USERNAME") == "" {
+		t.Fatal("INSIGHTCLOUDSEC_API_KEY or INSIGHTCLOUDSEC_USERNAME and INSIGHTCLOUDSEC_PASSWORD must be set for acceptance testing")
 	}
 }
 
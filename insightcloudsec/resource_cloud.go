@@ -2,29 +2,40 @@ package insightcloudsec
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	ics "github.com/gstotts/insightcloudsec"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const (
+	cloudAddPath      = "/v2/prototype/cloud/add"
+	cloudSettingsPath = "/v2/public/cloud/%s/settings"
+	cloudPausePath    = "/v2/public/clouds/pause"
+	cloudResumePath   = "/v2/public/clouds/resume"
+
+	// The status of a cloud whose harvesting is paused
+	CLOUD_STATUS_PAUSED = "PAUSED"
+)
+
 var (
-	// For use in ConflictsWith statements
-	AZR_ONLY_ATTR = []string{"tenant_id", "subscription_id", "app_id"}
-	AWS_ONLY_ATTR = []string{"account", "role_arn", "duration", "external_id", "session_name", "secret_key"}
-	GCE_ONLY_ATTR = []string{"project", "api_credentials"}
-
-	// Combinations of Attributes
-	AZR_AND_GCP_ATTR = append(AZR_ONLY_ATTR, GCE_ONLY_ATTR...)
-	AZR_AND_AWS_ATTR = append(AZR_ONLY_ATTR, AWS_ONLY_ATTR...)
-	AZR_AND_GCE_ATTR = append(AWS_ONLY_ATTR, GCE_ONLY_ATTR...)
+	// The per-provider blocks, exactly one of which is set
+	CLOUD_BLOCKS = []string{"aws", "azure", "gcp", "oci", "alibaba"}
+
+	// Statuses that mean the cloud will not finish harvesting without intervention
+	CLOUD_ERROR_STATUSES = []string{"ASSUME_ROLE_FAIL", "INVALID_CREDS", "PERMISSION_ERRORS", "ERROR"}
 )
 
 func resourceCloud() *schema.Resource {
@@ -33,6 +44,26 @@ func resourceCloud() *schema.Resource {
 		ReadContext:   resourceCloudRead,
 		UpdateContext: resourceCloudUpdate,
 		DeleteContext: resourceCloudDelete,
+		CustomizeDiff: customdiff.All(
+			validateCloudTypeAvailable,
+			validateCloudBlock,
+			validateCloudPartition,
+			validateAzureAuth,
+		),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCloudV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceCloudStateUpgradeV0,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -55,6 +86,24 @@ func resourceCloud() *schema.Resource {
 				Computed:    true,
 				Description: "The status of the cloud",
 			},
+			"harvesting_paused": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether harvesting of the cloud is paused, such as during a maintenance window or while it is decommissioned",
+			},
+			"wait_for_status": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A status to wait for the cloud to reach after it is created, such as ready.  Creation fails if the cloud reports an error status first",
+			},
+			"wait_timeout": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "20m",
+				ValidateDiagFunc: validateDuration,
+				Description:      "How long to wait for wait_for_status to be reached, as a duration such as 20m.  The wait is also limited by the create timeout",
+			},
 			"resource_id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -71,175 +120,389 @@ func resourceCloud() *schema.Resource {
 				Description: "The organization ID for the cloud",
 			},
 			"strategy_id": {
-				Type:        schema.TypeInt,
-				Computed:    true,
-				Description: "The harvesting strategy ID for the cloud",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The harvesting strategy ID for the cloud.  The strategy InsightCloudSec assigns is kept when not set",
 			},
 			"cloud_type": {
 				Type:             schema.TypeString,
 				Required:         true,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"AWS", "AZURE_ARM", "GCE"}, false)),
-				Description:      "The type of cloud being provisioned.  Supported Options: AWS, AZURE_ARM, GCE",
-			},
-			"tenant_id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCE_ATTR,
-				RequiredWith:  AZR_ONLY_ATTR,
-				Description:   "The tenant id for the cloud for AZURE_ARM cloud types",
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(cloudTypes(), false)),
+				Description:      "The type of cloud being provisioned.  Supported Options: " + strings.Join(cloudTypes(), ", "),
 			},
-			"app_id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCE_ATTR,
-				RequiredWith:  AZR_ONLY_ATTR,
-				Description:   "The application id assigned to the cloud for AZURE_ARM cloud types",
-			},
-			"subscription_id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCE_ATTR,
-				RequiredWith:  AZR_ONLY_ATTR,
-				Description:   "The subscription id assigned to the cloud for AZURE_ARM cloud types",
-			},
-			// Used in multiple clouds so must careful use ConflictsWith and RequiredWith
-			"api_key": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Sensitive:     true,
-				ConflictsWith: []string{},
-				RequiredWith:  []string{},
-				Description:   "The api key to utilize in accessing information for the cloud",
-			},
-			"account": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCP_ATTR,
-				RequiredWith:  AWS_ONLY_ATTR,
-				Description:   "The account number associated with the cloud for AWS cloud types",
-			},
-			"authentication_type": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				ConflictsWith:    AZR_AND_GCP_ATTR,
-				RequiredWith:     AWS_ONLY_ATTR,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"assume_role", "instance_assume_role"}, false)),
-				Description:      "The authentication type for use with AWS cloud types.  Supportred Options: assume_role or instance_assume_role",
-			},
-			"role_arn": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCP_ATTR,
-				RequiredWith:  AWS_ONLY_ATTR,
-				Description:   "The ARN of the role to assume for AWS cloud types",
-			},
-			// Not required for use with assume_role authentication method
-			"secret_key": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Sensitive:     true,
-				ConflictsWith: AZR_AND_GCP_ATTR,
-				Description:   "The secret key used in accessing the AWS cloud type when authenticating via instance_assume_role",
-			},
-			"duration": {
-				Type:          schema.TypeInt,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCP_ATTR,
-				Description:   "The duration associated with the authentication for AWS cloud types",
-			},
-			"external_id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCP_ATTR,
-				RequiredWith:  AWS_ONLY_ATTR,
-				Description:   "An optional unique identifier to include as part of the assume role handshake for AWS cloud types",
-			},
-			"session_name": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_GCP_ATTR,
-				RequiredWith:  AWS_ONLY_ATTR,
-				Description:   "A name to give the session for accessing AWS cloud types.  This name will be used when logging with CloudTrail",
-			},
-			"api_credentials": {
-				Type:          schema.TypeSet,
-				Optional:      true,
-				ConflictsWith: AZR_AND_AWS_ATTR,
-				RequiredWith:  GCE_ONLY_ATTR,
+			"aws": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_BLOCKS,
+				Description:  "The account and credentials for AWS, AWS_GOV and AWS_CHINA cloud types",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "service_account",
+						"account": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`^\d{12}$`), "must be a 12 digit AWS account number")),
+							Description:      "The account number associated with the cloud",
 						},
-						"project_id": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							RequiredWith: GCE_ONLY_ATTR,
+						"authentication_type": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"assume_role", "instance_assume_role"}, false)),
+							Description:      "The authentication type for the cloud.  Supported Options: assume_role or instance_assume_role",
 						},
-						"private_key_id": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							RequiredWith: GCE_ONLY_ATTR,
+						"role_arn": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`^arn:[a-z-]+:iam::\d{12}:role/`), "must be the ARN of an IAM role")),
+							Description:      "The ARN of the role to assume",
 						},
-						"private_key": {
+						// The API fills in a session name and duration when they are not set
+						"session_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "A name to give the session for accessing the cloud.  This name will be used when logging with CloudTrail",
+						},
+						"external_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "An optional unique identifier to include as part of the assume role handshake",
+						},
+						"duration": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(900, 43200)),
+							Description:      "The duration in seconds of the assumed role session",
+						},
+						"api_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The access key ID used with assume_role authentication",
+						},
+						// Not required for use with instance_assume_role authentication method
+						"secret_key": {
 							Type:         schema.TypeString,
 							Optional:     true,
 							Sensitive:    true,
-							RequiredWith: GCE_ONLY_ATTR,
+							RequiredWith: []string{"aws.0.api_key"},
+							Description:  "The secret access key used with assume_role authentication",
+						},
+					},
+				},
+			},
+			"azure": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_BLOCKS,
+				Description:  "The subscription and service principal for AZURE_ARM, AZURE_ARM_GOV and AZURE_ARM_CHINA cloud types",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tenant_id": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsUUID),
+							Description:      "The tenant id for the cloud",
+						},
+						"subscription_id": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsUUID),
+							Description:      "The subscription id assigned to the cloud",
+						},
+						"app_id": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsUUID),
+							Description:      "The application id of the service principal",
 						},
-						"client_email": {
+						"auth_type": {
 							Type:             schema.TypeString,
 							Optional:         true,
-							RequiredWith:     GCE_ONLY_ATTR,
-							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`[\w+=,.-]+@[\w.-]+\.[\w]+`), "must be a valid email address")),
+							Computed:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(AZURE_AUTH_TYPES, false)),
+							Description:      "The authentication type of the service principal.  Supported Options: standard (client secret in api_key), certificate or federated.  Defaults to standard",
 						},
-						"client_id": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							RequiredWith: GCE_ONLY_ATTR,
+						"authority_host": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Azure AD authority host of the cloud_type partition",
 						},
-						"auth_uri": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							RequiredWith: GCE_ONLY_ATTR,
+						"api_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The client secret of the service principal when auth_type is standard",
 						},
-						"token_uri": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							RequiredWith: GCE_ONLY_ATTR,
+						"certificate_pem": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ConflictsWith:    []string{"azure.0.certificate_pfx"},
+							ValidateDiagFunc: validateCertificatePEM,
+							Description:      "The PEM encoded certificate and private key of the service principal when auth_type is certificate",
+						},
+						"certificate_pfx": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ConflictsWith:    []string{"azure.0.certificate_pem"},
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+							Description:      "The base64 encoded PFX bundle of the service principal when auth_type is certificate",
 						},
-						"auth_provider_x509_cert_url": {
+						"certificate_password": {
 							Type:         schema.TypeString,
 							Optional:     true,
-							RequiredWith: GCE_ONLY_ATTR,
+							Sensitive:    true,
+							RequiredWith: []string{"azure.0.certificate_pfx"},
+							Description:  "The password protecting certificate_pfx",
 						},
-						"client_x509_cert_url": {
-							Type:         schema.TypeString,
+					},
+				},
+			},
+			"gcp": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_BLOCKS,
+				Description:  "The project and service account for GCE cloud types",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"project": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "The project associated with the cloud",
+						},
+						"credentials_json": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ExactlyOneOf:     []string{"gcp.0.credentials_json", "gcp.0.api_credentials"},
+							ValidateDiagFunc: validateGCPCredentialsJSON,
+							Description:      "The contents of the key file of the service account used to harvest the project, for example from file()",
+						},
+						"api_credentials": {
+							Type:         schema.TypeSet,
 							Optional:     true,
-							RequiredWith: GCE_ONLY_ATTR,
+							MaxItems:     1,
+							ExactlyOneOf: []string{"gcp.0.credentials_json", "gcp.0.api_credentials"},
+							Description:  "The key of the service account used to harvest the project, split into its fields.  An alternative to credentials_json",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Default:  "service_account",
+									},
+									"project_id": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"private_key_id": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"private_key": {
+										Type:      schema.TypeString,
+										Required:  true,
+										Sensitive: true,
+									},
+									"client_email": {
+										Type:             schema.TypeString,
+										Required:         true,
+										ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`[\w+=,.-]+@[\w.-]+\.[\w]+`), "must be a valid email address")),
+									},
+									"client_id": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"auth_uri": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"token_uri": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"auth_provider_x509_cert_url": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"client_x509_cert_url": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"oci": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_BLOCKS,
+				Description:  "The tenancy and API signing key for OCI cloud types",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tenancy_ocid": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(ociTenancyPattern, "must be a tenancy OCID")),
+							Description:      "The OCID of the tenancy",
+						},
+						"user_ocid": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(ociUserPattern, "must be a user OCID")),
+							Description:      "The OCID of the user whose API key is used",
+						},
+						"fingerprint": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(ociFingerprintPattern, "must be an API key fingerprint such as 12:34:56:78:90:ab:cd:ef:12:34:56:78:90:ab:cd:ef")),
+							Description:      "The fingerprint of the API signing key",
+						},
+						"private_key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "The PEM encoded API signing key",
+						},
+						"region": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(ociRegionPattern, "must be an OCI region such as us-ashburn-1")),
+							Description:      "The home region of the tenancy",
 						},
 					},
 				},
 			},
-			"project": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: AZR_AND_AWS_ATTR,
-				RequiredWith:  GCE_ONLY_ATTR,
+			"alibaba": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_BLOCKS,
+				Description:  "The RAM access key for ALICLOUD cloud types",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"access_key_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The RAM access key ID",
+						},
+						"access_key_secret": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "The RAM access key secret",
+						},
+						"ram_role_arn": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(alibabaRoleArnPattern, "must be a RAM role ARN such as acs:ram::1234567890123456:role/InsightCloudSec")),
+							Description:      "An optional RAM role to assume with the access key",
+						},
+					},
+				},
 			},
 		},
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceCloudImport,
 		},
 	}
 }
 
+// resourceCloudImport accepts the internal ID or one of name:<cloud name>,
+// account:<account/subscription/project id> or resource_id:<resource id>.
+func resourceCloudImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cloud ics.Cloud
+	importID := d.Id()
+	kind, value, found := strings.Cut(importID, ":")
+
+	switch {
+	case found && (kind == "name" || kind == "account" || kind == "resource_id"):
+		cloud, err = findCloud(c, kind, value)
+	default:
+		if _, err := strconv.Atoi(importID); err != nil {
+			return nil, fmt.Errorf("[ERROR] Invalid import ID %q, expected the cloud ID or one of name:<cloud name>, account:<account id> or resource_id:<resource id>", importID)
+		}
+		cloud, err = findCloud(c, "id", importID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Cloud Resolved for Import %s: \n%v", importID, cloud))
+
+	d.SetId(strconv.Itoa(cloud.ID))
+	d.Set("cloud_type", cloud.CloudTypeID)
+	d.Set("wait_timeout", "20m")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// findCloud looks up a single cloud by its id, name, account or resource ID
+func findCloud(c *apiClient, kind, value string) (ics.Cloud, error) {
+	clouds, err := c.Clouds.List()
+	if err != nil {
+		return ics.Cloud{}, err
+	}
+
+	var matches []ics.Cloud
+	for _, cloud := range clouds.Clouds {
+		var field string
+		switch kind {
+		case "id":
+			field = strconv.Itoa(cloud.ID)
+		case "name":
+			field = cloud.Name
+		case "account":
+			field = cloud.AccountID
+		case "resource_id":
+			field = cloud.ResourceID
+		}
+		if field == value {
+			matches = append(matches, cloud)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return ics.Cloud{}, fmt.Errorf("[ERROR] No cloud found with %s %q", kind, value)
+	case 1:
+		return matches[0], nil
+	default:
+		return ics.Cloud{}, fmt.Errorf("[ERROR] %d clouds found with %s %q, use the cloud ID instead", len(matches), kind, value)
+	}
+}
+
 func resourceCloudCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud", "created")
+	}
 	var diags diag.Diagnostics
-	var err error
 	var cloud ics.Cloud
 
 	// Common Parameters
@@ -248,13 +511,25 @@ func resourceCloudCreate(ctx context.Context, d *schema.ResourceData, m interfac
 		CloudType: d.Get("cloud_type").(string),
 	}
 
+	if _, ok := CLOUD_PARTITIONS[params.CloudType]; !ok {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unsupported Cloud Type",
+			Detail:   fmt.Sprintf("The cloud type %s is not supported.  Supported Options: %s", params.CloudType, strings.Join(cloudTypes(), ", ")),
+		})
+		return diags
+	}
+
 	// Azure Cloud Accounts
-	if params.CloudType == "AZURE_ARM" {
-		params.AuthType = "standard"
-		params.TenantID = d.Get("tenant_id").(string)
-		params.AppID = d.Get("app_id").(string)
-		params.SubscriptionID = d.Get("subscription_id").(string)
-		params.ApiKeyOrCert = d.Get("api_key").(string)
+	if cloudFamily(params.CloudType) == "AZURE_ARM" {
+		params.AuthType = azureAuthType(d)
+		params.TenantID = d.Get("azure.0.tenant_id").(string)
+		params.AppID = d.Get("azure.0.app_id").(string)
+		params.SubscriptionID = d.Get("azure.0.subscription_id").(string)
+		params.ApiKeyOrCert, err = azureCredential(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 
 		cloud, err = c.Clouds.AddAzureCloud(ics.AzureCloudAccount{CreationParameters: params})
 		if err != nil {
@@ -263,7 +538,7 @@ func resourceCloudCreate(ctx context.Context, d *schema.ResourceData, m interfac
 				Summary:  "Error Adding Azure Cloud",
 				Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
 					"An error was returned when attempting to add an Azure Cloud to InsightCloudSec.",
-					"This could be the result of an incorrect tenant_id, subscription_id or app_id.",
+					fmt.Sprintf("This could be the result of an incorrect tenant_id, subscription_id, app_id or %s credential.", params.AuthType),
 					"Error from API:", err),
 			})
 			return diags
@@ -273,19 +548,19 @@ func resourceCloudCreate(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 
 	// AWS Cloud Accounts
-	if params.CloudType == "AWS" {
-		params.RoleArn = d.Get("role_arn").(string)
-		params.Duration = d.Get("duration").(int)
-		params.SessionName = d.Get("session_name").(string)
-		params.ExternalID = d.Get("external_id").(string)
+	if cloudFamily(params.CloudType) == "AWS" {
+		params.RoleArn = d.Get("aws.0.role_arn").(string)
+		params.Duration = d.Get("aws.0.duration").(int)
+		params.SessionName = d.Get("aws.0.session_name").(string)
+		params.ExternalID = d.Get("aws.0.external_id").(string)
 
-		auth_type := strings.ToLower(d.Get("authentication_type").(string))
+		auth_type := strings.ToLower(d.Get("aws.0.authentication_type").(string))
 		params.AuthType = auth_type
 
 		if auth_type == "assume_role" {
 			// AWS STS Assume Role (Instance Assume does not require)
-			params.ApiKeyOrCert = d.Get("api_key").(string)
-			params.SecretKey = d.Get("secret_key").(string)
+			params.ApiKeyOrCert = d.Get("aws.0.api_key").(string)
+			params.SecretKey = d.Get("aws.0.secret_key").(string)
 			tflog.Debug(ctx, fmt.Sprintf("Setting up Assume Role for: %s", params.Name))
 		} else if auth_type != "instance_assume_role" {
 			return diag.FromErr(fmt.Errorf("[ERROR] Invalid authentication type,  must be assume_role or instance_assume_role for AWS clouds"))
@@ -309,9 +584,12 @@ func resourceCloudCreate(ctx context.Context, d *schema.ResourceData, m interfac
 
 	// GCE Cloud Accounts
 
-	if params.CloudType == "GCE" {
-		params.GCPAuth = gcpCredentialsExpand(d.Get("api_credentials").(*schema.Set))
-		params.Project = d.Get("project").(string)
+	if cloudFamily(params.CloudType) == "GCE" {
+		params.GCPAuth, err = gcpCredentials(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		params.Project = d.Get("gcp.0.project").(string)
 
 		cloud, err = c.Clouds.AddGCPCloud(ics.GCPCloudAccount{CreationParameters: params})
 		if err != nil {
@@ -329,14 +607,153 @@ func resourceCloudCreate(ctx context.Context, d *schema.ResourceData, m interfac
 		tflog.Debug(ctx, fmt.Sprintf("GCP Cloud Returned from API: \n%v", cloud))
 	}
 
+	// OCI Cloud Accounts
+	if cloudFamily(params.CloudType) == "OCI" {
+		cloud, err = addOCICloud(ctx, c, d)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Error Adding OCI Cloud",
+				Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
+					"An error was returned when attempting to add an OCI Cloud to InsightCloudSec.",
+					"This could be the result of an incorrect tenancy_ocid, user_ocid, fingerprint or private_key, or an API key that has not been uploaded for the user.",
+					"Error from API:", err),
+			})
+			return diags
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("OCI Cloud Returned from API: \n%v", cloud))
+	}
+
+	// Alibaba Cloud Accounts
+	if cloudFamily(params.CloudType) == "ALICLOUD" {
+		cloud, err = addAlibabaCloud(ctx, c, d)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Error Adding Alibaba Cloud",
+				Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
+					"An error was returned when attempting to add an Alibaba Cloud to InsightCloudSec.",
+					"This could be the result of an incorrect access_key_id or access_key_secret, or a ram_role_arn the access key cannot assume.",
+					"Error from API:", err),
+			})
+			return diags
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Alibaba Cloud Returned from API: \n%v", cloud))
+	}
+
+	// The OCI and Alibaba responses are decoded here rather than by the ics client
+	if cloud.ID == 0 {
+		return diag.FromErr(fmt.Errorf("[ERROR] InsightCloudSec did not return an ID for the cloud %s", params.Name))
+	}
 	d.SetId(strconv.Itoa(cloud.ID))
+
+	if strategyID, ok := d.GetOk("strategy_id"); ok {
+		if err := assignHarvestingStrategy(ctx, c, strategyID.(int), cloud.ResourceID); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Error Assigning Harvesting Strategy",
+				Detail: fmt.Sprintf("%s\n\n%s\n%s",
+					fmt.Sprintf("The cloud %s was added but the harvesting strategy %d could not be assigned.", params.Name, strategyID),
+					"Error from API:", err),
+			})
+			resourceCloudRead(ctx, d, m)
+			return diags
+		}
+	}
+
+	if status := d.Get("wait_for_status").(string); status != "" {
+		timeout, _ := time.ParseDuration(d.Get("wait_timeout").(string))
+		if _, err := waitForCloudStatus(ctx, c, cloud.ID, status, timeout); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Error Waiting for Cloud Status",
+				Detail: fmt.Sprintf("%s\n\n%s",
+					fmt.Sprintf("The cloud %s was added but did not reach the %s status.", params.Name, status),
+					err),
+			})
+			resourceCloudRead(ctx, d, m)
+			return diags
+		}
+	}
+
+	// Paused after waiting, as a paused cloud does not finish its first harvest
+	if d.Get("harvesting_paused").(bool) {
+		if err := setCloudHarvestingPaused(ctx, c, cloud.ResourceID, true); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Error Pausing Harvesting",
+				Detail: fmt.Sprintf("%s\n\n%s\n%s",
+					fmt.Sprintf("The cloud %s was added but harvesting could not be paused.", params.Name),
+					"Error from API:", err),
+			})
+			resourceCloudRead(ctx, d, m)
+			return diags
+		}
+	}
+
 	resourceCloudRead(ctx, d, m)
 
 	return diags
 }
 
+// setCloudHarvestingPaused pauses or resumes harvesting of a cloud
+func setCloudHarvestingPaused(ctx context.Context, c *apiClient, resourceID string, paused bool) error {
+	path := cloudResumePath
+	if paused {
+		path = cloudPausePath
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Setting harvesting paused to %t for %s", paused, resourceID))
+	return c.request(ctx, http.MethodPost, path, map[string]interface{}{"resource_ids": []string{resourceID}}, nil)
+}
+
+// waitForCloudStatus polls the cloud until it reports the target status, failing
+// early if it reports one of CLOUD_ERROR_STATUSES instead.
+func waitForCloudStatus(ctx context.Context, c *apiClient, id int, target string, timeout time.Duration) (ics.Cloud, error) {
+	var lastStatus string
+	stateConf := &retry.StateChangeConf{
+		Pending:    []string{"pending"},
+		Target:     []string{"reached"},
+		Timeout:    timeout,
+		Delay:      5 * time.Second,
+		MinTimeout: 5 * time.Second,
+		Refresh: func() (interface{}, string, error) {
+			cloud, err := c.Clouds.GetByID(id)
+			if err != nil {
+				return nil, "", err
+			}
+
+			lastStatus = cloud.Status
+			tflog.Debug(ctx, fmt.Sprintf("Cloud %d has status %s, waiting for %s", id, cloud.Status, target))
+
+			if strings.EqualFold(cloud.Status, target) {
+				return cloud, "reached", nil
+			}
+			for _, status := range CLOUD_ERROR_STATUSES {
+				if strings.EqualFold(cloud.Status, status) {
+					return cloud, "", fmt.Errorf("[ERROR] Cloud entered the %s status while waiting for %s", cloud.Status, target)
+				}
+			}
+			return cloud, "pending", nil
+		},
+	}
+
+	result, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if lastStatus != "" {
+			return ics.Cloud{}, fmt.Errorf("%s (last seen status: %s)", err, lastStatus)
+		}
+		return ics.Cloud{}, err
+	}
+	return result.(ics.Cloud), nil
+}
+
 func resourceCloudRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	var diags diag.Diagnostics
 
 	id, err := strconv.Atoi(d.Id())
@@ -350,20 +767,187 @@ func resourceCloudRead(ctx context.Context, d *schema.ResourceData, m interface{
 	}
 
 	d.Set("name", cloud.Name)
-	d.Set("account", cloud.AccountID)
 	d.Set("resource_id", cloud.ResourceID)
 	d.Set("group_resource_id", cloud.GroupResourceID)
+	d.Set("org_resource_id", cloud.CloudOrgID)
 	d.Set("status", cloud.Status)
+	d.Set("harvesting_paused", strings.EqualFold(cloud.Status, CLOUD_STATUS_PAUSED))
 	d.Set("creation_time", cloud.Created)
 	d.Set("strategy_id", cloud.StrategyID)
 	d.Set("cloud_type", cloud.CloudTypeID)
 
+	settings, err := getCloudAccountSettings(ctx, c, cloud.ResourceID)
+	if err != nil {
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return diag.FromErr(err)
+		}
+
+		// Older instances do not expose account settings, so only the account is refreshed
+		tflog.Warn(ctx, fmt.Sprintf("Account settings are unavailable for %s, skipping drift detection of credentials", cloud.ResourceID))
+		settings = cloudAccountSettings{}
+	}
+	if settings.AccountID == "" {
+		settings.AccountID = cloud.AccountID
+	}
+
+	flattenCloudAccountSettings(d, cloud.CloudTypeID, settings)
+
 	return diags
 }
 
+// cloudAccountSettings holds the authentication settings of a cloud as reported
+// by the console.  Secrets are never returned by the API.
+type cloudAccountSettings struct {
+	AccountID          string `json:"account_id"`
+	AuthenticationType string `json:"authentication_type"`
+	RoleArn            string `json:"role_arn"`
+	ExternalID         string `json:"external_id"`
+	SessionName        string `json:"session_name"`
+	Duration           int    `json:"duration"`
+	TenantID           string `json:"tenant_id"`
+	AppID              string `json:"app_id"`
+	SubscriptionID     string `json:"subscription_id"`
+	Project            string `json:"project"`
+	TenancyID          string `json:"tenancy_id"`
+	UserID             string `json:"user_id"`
+	Fingerprint        string `json:"fingerprint"`
+	Region             string `json:"home_region"`
+	AccessKeyID        string `json:"access_key_id"`
+}
+
+func getCloudAccountSettings(ctx context.Context, c *apiClient, resourceID string) (cloudAccountSettings, error) {
+	var settings cloudAccountSettings
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf(cloudSettingsPath, resourceID), nil, &settings)
+	return settings, err
+}
+
+// flattenCloudAccountSettings sets every non-secret attribute in the block for
+// the cloud type so that changes made in the console show up as drift.  Secrets
+// such as api_key, secret_key and api_credentials are left as configured.
+func flattenCloudAccountSettings(d *schema.ResourceData, cloudType string, settings cloudAccountSettings) {
+	values := map[string]interface{}{}
+
+	switch cloudFamily(cloudType) {
+	case "AWS":
+		values["account"] = settings.AccountID
+		if settings.AuthenticationType != "" {
+			values["authentication_type"] = settings.AuthenticationType
+			values["role_arn"] = settings.RoleArn
+			values["external_id"] = settings.ExternalID
+			values["session_name"] = settings.SessionName
+			values["duration"] = settings.Duration
+		}
+
+	case "AZURE_ARM":
+		if settings.SubscriptionID == "" {
+			settings.SubscriptionID = settings.AccountID
+		}
+		values["subscription_id"] = settings.SubscriptionID
+		values["authority_host"] = CLOUD_PARTITIONS[cloudType].AuthorityHost
+		if settings.AuthenticationType != "" {
+			values["auth_type"] = settings.AuthenticationType
+		}
+		if settings.TenantID != "" {
+			values["tenant_id"] = settings.TenantID
+			values["app_id"] = settings.AppID
+		}
+
+	case "GCE":
+		if settings.Project == "" {
+			settings.Project = settings.AccountID
+		}
+		values["project"] = settings.Project
+
+	case "OCI":
+		if settings.TenancyID == "" {
+			settings.TenancyID = settings.AccountID
+		}
+		values["tenancy_ocid"] = settings.TenancyID
+		if settings.UserID != "" {
+			values["user_ocid"] = settings.UserID
+			values["fingerprint"] = settings.Fingerprint
+			values["region"] = settings.Region
+		}
+
+	case "ALICLOUD":
+		if settings.AccessKeyID != "" {
+			values["access_key_id"] = settings.AccessKeyID
+			values["ram_role_arn"] = settings.RoleArn
+		}
+	}
+
+	if block := CLOUD_PARTITIONS[cloudType].Block; block != "" {
+		setCloudBlock(d, block, values)
+	}
+}
+
+// setCloudBlock merges values into the single element of a provider block,
+// keeping any attributes such as secrets that are not being set
+func setCloudBlock(d *schema.ResourceData, block string, values map[string]interface{}) {
+	merged := map[string]interface{}{}
+	if current, ok := d.Get(block).([]interface{}); ok && len(current) > 0 && current[0] != nil {
+		for k, v := range current[0].(map[string]interface{}) {
+			merged[k] = v
+		}
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	d.Set(block, []interface{}{merged})
+}
+
+// cloudUpdatePath is the endpoint for updating clouds the ics client does not wrap
+func cloudUpdatePath(d *schema.ResourceData) string {
+	return fmt.Sprintf("/v2/prototype/cloud/%s/update", d.Get("resource_id").(string))
+}
+
 func resourceCloudUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud", "updated")
+	}
+
+	// The cloud is updated before its strategy and harvesting are changed, so a
+	// resumed cloud harvests with the new settings.  Until every step succeeds
+	// the previous state is kept, and a failed step is retried by the next apply.
+	d.Partial(true)
+
+	// Waiting only applies on creation, so there is nothing to send to the API
+	if d.HasChangesExcept("wait_for_status", "wait_timeout", "last_updated", "strategy_id", "harvesting_paused") {
+		if diags := updateCloudSettings(ctx, c, d); diags.HasError() {
+			return diags
+		}
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+
+	if d.HasChange("strategy_id") {
+		if strategyID, ok := d.GetOk("strategy_id"); ok {
+			err = assignHarvestingStrategy(ctx, c, strategyID.(int), d.Get("resource_id").(string))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if d.HasChange("harvesting_paused") {
+		err = setCloudHarvestingPaused(ctx, c, d.Get("resource_id").(string), d.Get("harvesting_paused").(bool))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.Partial(false)
+	return resourceCloudRead(ctx, d, m)
+}
+
+// updateCloudSettings sends the name and changed account settings of the cloud
+func updateCloudSettings(ctx context.Context, c *apiClient, d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
-	c := m.(*ics.Client)
+	var err error
 
 	// Common Parameters
 	params := ics.CloudAccountParameters{
@@ -371,78 +955,112 @@ func resourceCloudUpdate(ctx context.Context, d *schema.ResourceData, m interfac
 		CloudType: d.Get("cloud_type").(string),
 	}
 
-	// Azure Cloud Accounts Parameters
-	if params.CloudType == "AZURE_ARM" {
-		params.AuthType = "standard"
-		params.TenantID = d.Get("tenant_id").(string)
-		params.AppID = d.Get("app_id").(string)
-		params.SubscriptionID = d.Get("subscription_id").(string)
-		params.ApiKeyOrCert = d.Get("api_key").(string)
-	}
-
-	// AWS Cloud Account Parameters
-	if params.CloudType == "AWS" {
-		params.RoleArn = d.Get("aws.0.role_arn").(string)
-		params.Duration = d.Get("aws.0.duration").(int)
-		params.SessionName = d.Get("aws.0.session_name").(string)
-		params.ExternalID = d.Get("aws.0.external_id").(string)
-		params.CloudType = "AWS"
+	// The identifying attributes force a new cloud, so they are always sent as is.
+	// Credential fields are only sent when they have changed, which lets keys be
+	// rotated without affecting the rest of the cloud's configuration.
+	switch cloudFamily(params.CloudType) {
+	case "AZURE_ARM":
+		params.AuthType = azureAuthType(d)
+		params.TenantID = d.Get("azure.0.tenant_id").(string)
+		params.SubscriptionID = d.Get("azure.0.subscription_id").(string)
+		if d.HasChange("azure.0.app_id") {
+			params.AppID = d.Get("azure.0.app_id").(string)
+		}
+		if d.HasChanges("azure.0.auth_type", "azure.0.api_key", "azure.0.certificate_pem", "azure.0.certificate_pfx", "azure.0.certificate_password") {
+			params.ApiKeyOrCert, err = azureCredential(d)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
 
-		auth_type := strings.ToLower(d.Get("authentication_type").(string))
+	case "AWS":
+		auth_type := strings.ToLower(d.Get("aws.0.authentication_type").(string))
 		params.AuthType = auth_type
 
+		if d.HasChange("aws.0.role_arn") {
+			params.RoleArn = d.Get("aws.0.role_arn").(string)
+		}
+		if d.HasChange("aws.0.duration") {
+			params.Duration = d.Get("aws.0.duration").(int)
+		}
+		if d.HasChange("aws.0.session_name") {
+			params.SessionName = d.Get("aws.0.session_name").(string)
+		}
+		if d.HasChange("aws.0.external_id") {
+			params.ExternalID = d.Get("aws.0.external_id").(string)
+		}
+
 		if auth_type == "assume_role" {
 			// AWS STS Assume Role (Instance Assume does not require)
-			params.ApiKeyOrCert = d.Get("api_key").(string)
-			params.SecretKey = d.Get("secret_key").(string)
+			if d.HasChange("aws.0.api_key") {
+				params.ApiKeyOrCert = d.Get("aws.0.api_key").(string)
+			}
+			if d.HasChange("aws.0.secret_key") {
+				params.SecretKey = d.Get("aws.0.secret_key").(string)
+			}
 		} else if auth_type != "instance_assume_role" {
 			return diag.FromErr(fmt.Errorf("[ERROR] Invalid authentication type,  must be assume_role or instance_assume_role for AWS clouds"))
 		}
-	}
 
-	// GCE Cloud Accounts
-	if params.CloudType == "GCE" {
-		params.GCPAuth = gcpCredentialsExpand(d.Get("api_credentials").(*schema.Set))
+	case "GCE":
+		params.Project = d.Get("gcp.0.project").(string)
+		if d.HasChanges("gcp.0.credentials_json", "gcp.0.api_credentials") {
+			params.GCPAuth, err = gcpCredentials(d)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
 	}
 
 	id, _ := strconv.Atoi(d.Id())
 	tflog.Debug(ctx, fmt.Sprintf("Updating Cloud ID: \n%v\n", id))
-	_, err := c.Clouds.Update(id, params)
+
+	switch cloudFamily(params.CloudType) {
+	case "OCI":
+		err = updateOCICloud(ctx, c, d)
+	case "ALICLOUD":
+		err = updateAlibabaCloud(ctx, c, d)
+	default:
+		_, err = c.Clouds.Update(id, params)
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Error Updating Cloud",
+			Detail: fmt.Sprintf("%s\n\n%s\n%s",
+				fmt.Sprintf("An error was returned when attempting to update the cloud %s in InsightCloudSec.", params.Name),
+				"Error from API:", err),
+		})
+		return diags
 	}
-
-	d.Set("last_updated", time.Now().Format(time.RFC850))
-	resourceCloudRead(ctx, d, m)
-
 	return diags
 }
 
 func resourceCloudDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud", "deleted")
+	}
 	var diags diag.Diagnostics
 
-	err := c.Clouds.Delete(d.Get("resource_id").(string))
+	err = c.Clouds.Delete(d.Get("resource_id").(string))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	return diags
 }
 
-func gcpCredentialsExpand(credSet *schema.Set) (creds ics.GCPAccountApiCreds) {
-	o := credSet.List()[0].(map[string]interface{})
-	creds = ics.GCPAccountApiCreds{
-		Type:                    o["type"].(string),
-		ProjectID:               o["project_id"].(string),
-		PrivateKeyID:            o["private_key_id"].(string),
-		PrivateKey:              o["private_key"].(string),
-		AuthURI:                 o["auth_uri"].(string),
-		TokenURI:                o["token_uri"].(string),
-		ClientEmail:             o["client_email"].(string),
-		ClientID:                o["client_id"].(string),
-		Clientx509CertUrl:       o["client_x509_cert_url"].(string),
-		AuthProviderx509CertURL: o["auth_provider_x509_cert_url"].(string),
-	}
-
-	return creds
+func validateDuration(v interface{}, p cty.Path) diag.Diagnostics {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid Duration",
+			Detail:        fmt.Sprintf("%q is not a valid duration such as 30s, 20m or 1h: %s", v, err),
+			AttributePath: p,
+		}}
+	}
+	return nil
 }
@@ -0,0 +1,60 @@
+package insightcloudsec
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	ics "github.com/gstotts/insightcloudsec"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var alibabaRoleArnPattern = regexp.MustCompile(`^acs:ram::\d+:role/.+$`)
+
+// alibabaCloudParameters are the creation parameters for Alibaba clouds, which
+// the ics client does not wrap
+type alibabaCloudParameters struct {
+	CloudType       string `json:"cloud_type"`
+	Name            string `json:"name"`
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	AccessKeySecret string `json:"access_key_secret,omitempty"`
+	RoleArn         string `json:"role_arn,omitempty"`
+}
+
+// alibabaUpdateFields maps the alibaba block attributes onto their update parameters
+var alibabaUpdateFields = map[string]string{
+	"access_key_id":     "access_key_id",
+	"access_key_secret": "access_key_secret",
+	"ram_role_arn":      "role_arn",
+}
+
+func addAlibabaCloud(ctx context.Context, c *apiClient, d *schema.ResourceData) (ics.Cloud, error) {
+	var cloud ics.Cloud
+	params := alibabaCloudParameters{
+		CloudType:       d.Get("cloud_type").(string),
+		Name:            d.Get("name").(string),
+		AccessKeyID:     d.Get("alibaba.0.access_key_id").(string),
+		AccessKeySecret: d.Get("alibaba.0.access_key_secret").(string),
+		RoleArn:         d.Get("alibaba.0.ram_role_arn").(string),
+	}
+
+	err := c.request(ctx, http.MethodPost, cloudAddPath, map[string]interface{}{"creation_params": params}, &cloud)
+	return cloud, err
+}
+
+// updateAlibabaCloud sends the name and only the credential fields that changed.
+// Changed fields are sent even when empty so a removed ram_role_arn is cleared.
+func updateAlibabaCloud(ctx context.Context, c *apiClient, d *schema.ResourceData) error {
+	params := map[string]interface{}{
+		"cloud_type": d.Get("cloud_type").(string),
+		"name":       d.Get("name").(string),
+	}
+	for attr, field := range alibabaUpdateFields {
+		key := "alibaba.0." + attr
+		if d.HasChange(key) {
+			params[field] = d.Get(key).(string)
+		}
+	}
+
+	return c.request(ctx, http.MethodPost, cloudUpdatePath(d), map[string]interface{}{"creation_params": params}, nil)
+}
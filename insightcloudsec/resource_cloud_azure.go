@@ -0,0 +1,138 @@
+package insightcloudsec
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	AZURE_AUTH_STANDARD    = "standard"
+	AZURE_AUTH_CERTIFICATE = "certificate"
+	AZURE_AUTH_FEDERATED   = "federated"
+
+	// Workload identity federation is not available on older instances
+	azureFederatedMinVersion = "23.4.11"
+)
+
+var AZURE_AUTH_TYPES = []string{AZURE_AUTH_STANDARD, AZURE_AUTH_CERTIFICATE, AZURE_AUTH_FEDERATED}
+
+// azureAuthType returns the configured Azure authentication type, which defaults
+// to a client secret passed in api_key.
+func azureAuthType(d interface{ Get(string) interface{} }) string {
+	if authType, _ := d.Get("azure.0.auth_type").(string); authType != "" {
+		return authType
+	}
+	return AZURE_AUTH_STANDARD
+}
+
+// azureCredential returns the secret or PEM certificate to send for the
+// configured authentication type.  PFX bundles are converted to PEM.
+func azureCredential(d *schema.ResourceData) (string, error) {
+	switch azureAuthType(d) {
+	case AZURE_AUTH_CERTIFICATE:
+		if cert := d.Get("azure.0.certificate_pem").(string); cert != "" {
+			return cert, nil
+		}
+		return pfxToPEM(d.Get("azure.0.certificate_pfx").(string), d.Get("azure.0.certificate_password").(string))
+	case AZURE_AUTH_FEDERATED:
+		return "", nil
+	default:
+		return d.Get("azure.0.api_key").(string), nil
+	}
+}
+
+func pfxToPEM(encoded, password string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR] certificate_pfx must be base64 encoded: %s", err)
+	}
+
+	// DecodeChain also reads the AES encrypted bundles exported by OpenSSL 3 and Azure
+	key, cert, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR] Unable to read certificate_pfx, check certificate_password: %s", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR] Unsupported private key in certificate_pfx: %s", err)
+	}
+
+	var out strings.Builder
+	out.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	for _, ca := range caCerts {
+		out.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}))
+	}
+	out.Write(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	return out.String(), nil
+}
+
+// validateCertificatePEM checks that a certificate and its private key are both present
+func validateCertificatePEM(v interface{}, p cty.Path) diag.Diagnostics {
+	var hasCert, hasKey bool
+	rest := []byte(v.(string))
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		hasCert = hasCert || block.Type == "CERTIFICATE"
+		hasKey = hasKey || strings.HasSuffix(block.Type, "PRIVATE KEY")
+	}
+
+	if !hasCert || !hasKey {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid Certificate",
+			Detail:        "certificate_pem must contain both a PEM encoded CERTIFICATE and its PRIVATE KEY",
+			AttributePath: p,
+		}}
+	}
+	return nil
+}
+
+// validateAzureAuth checks that only the inputs for the chosen auth_type are set
+func validateAzureAuth(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if len(d.Get("azure").([]interface{})) == 0 {
+		return nil
+	}
+
+	set := func(key string) bool {
+		key = "azure.0." + key
+		return !d.NewValueKnown(key) || d.Get(key).(string) != ""
+	}
+
+	authType := azureAuthType(d)
+	switch authType {
+	case AZURE_AUTH_STANDARD:
+		if set("certificate_pem") || set("certificate_pfx") {
+			return fmt.Errorf("[ERROR] certificate_pem and certificate_pfx require auth_type = %q", AZURE_AUTH_CERTIFICATE)
+		}
+	case AZURE_AUTH_CERTIFICATE:
+		if set("api_key") {
+			return fmt.Errorf("[ERROR] api_key cannot be used with auth_type = %q, use certificate_pem or certificate_pfx", AZURE_AUTH_CERTIFICATE)
+		}
+		if set("certificate_pem") == set("certificate_pfx") && d.NewValueKnown("azure.0.certificate_pem") && d.NewValueKnown("azure.0.certificate_pfx") {
+			return fmt.Errorf("[ERROR] exactly one of certificate_pem or certificate_pfx must be set when auth_type = %q", AZURE_AUTH_CERTIFICATE)
+		}
+	case AZURE_AUTH_FEDERATED:
+		for _, key := range []string{"api_key", "certificate_pem", "certificate_pfx"} {
+			if set(key) {
+				return fmt.Errorf("[ERROR] %s cannot be used with auth_type = %q", key, AZURE_AUTH_FEDERATED)
+			}
+		}
+		if c, ok := m.(*apiClient); ok && !c.supportsVersion(azureFederatedMinVersion) {
+			return fmt.Errorf("[ERROR] auth_type = %q requires InsightCloudSec %s or later, this instance is %s", AZURE_AUTH_FEDERATED, azureFederatedMinVersion, c.serverVersion)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package insightcloudsec
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testCertificatePEM(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "insightcloudsec-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestValidateCertificatePEM(t *testing.T) {
+	cert, key := testCertificatePEM(t)
+
+	cases := map[string]struct {
+		value string
+		valid bool
+	}{
+		"certificate and key": {cert + key, true},
+		"certificate only":    {cert, false},
+		"key only":            {key, false},
+		"not pem":             {"not a certificate", false},
+	}
+
+	for name, tc := range cases {
+		diags := validateCertificatePEM(tc.value, cty.Path{})
+		if diags.HasError() == tc.valid {
+			t.Errorf("%s: expected valid to be %t, got %v", name, tc.valid, diags)
+		}
+	}
+}
+
+func TestAzureCredential(t *testing.T) {
+	cert, key := testCertificatePEM(t)
+
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		"standard":    {map[string]interface{}{"api_key": "secret"}, "secret"},
+		"certificate": {map[string]interface{}{"auth_type": "certificate", "certificate_pem": cert + key}, cert + key},
+		"federated":   {map[string]interface{}{"auth_type": "federated"}, ""},
+	}
+
+	for name, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceCloud().Schema, map[string]interface{}{"azure": []interface{}{tc.raw}})
+		got, err := azureCredential(d)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", name, tc.expected, got)
+		}
+	}
+}
+
+func TestPFXToPEM(t *testing.T) {
+	// Exported by OpenSSL 3 with its default AES-256-CBC encryption
+	data, err := os.ReadFile("testdata/service_principal.pfx")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	cases := map[string]struct {
+		password string
+		success  bool
+	}{
+		"correct password": {"insightcloudsec", true},
+		"wrong password":   {"incorrect", false},
+	}
+
+	for name, tc := range cases {
+		got, err := pfxToPEM(encoded, tc.password)
+		if (err == nil) != tc.success {
+			t.Errorf("%s: expected success to be %t, got err: %v", name, tc.success, err)
+			continue
+		}
+		if !tc.success {
+			continue
+		}
+		if !strings.Contains(got, "BEGIN CERTIFICATE") || !strings.Contains(got, "BEGIN PRIVATE KEY") {
+			t.Errorf("%s: expected a certificate and private key, got %q", name, got)
+		}
+		if diags := validateCertificatePEM(got, cty.Path{}); diags.HasError() {
+			t.Errorf("%s: converted PEM is not valid: %v", name, diags)
+		}
+	}
+}
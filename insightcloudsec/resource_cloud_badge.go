@@ -0,0 +1,139 @@
+package insightcloudsec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudBadge() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudBadgeCreate,
+		ReadContext:   resourceCloudBadgeRead,
+		UpdateContext: resourceCloudBadgeUpdate,
+		DeleteContext: resourceCloudBadgeDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudBadgeImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"cloud_resource_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The resource_id of the cloud the badge is added to",
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The key of the badge",
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The value of the badge",
+			},
+		},
+	}
+}
+
+// The ID is <cloud resource_id>/<key>, as resource IDs contain colons but no slashes
+func resourceCloudBadgeImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	resourceID, key, found := strings.Cut(d.Id(), "/")
+	if !found || resourceID == "" || key == "" {
+		return nil, fmt.Errorf("[ERROR] Invalid import ID %q, expected <cloud resource_id>/<badge key>", d.Id())
+	}
+	d.Set("cloud_resource_id", resourceID)
+	d.Set("key", key)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceCloudBadgeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_badge", "created")
+	}
+
+	resourceID := d.Get("cloud_resource_id").(string)
+	badge := cloudBadge{Key: d.Get("key").(string), Value: d.Get("value").(string)}
+	if err := setResourceBadges(ctx, c, cloudBadgesCreatePath, []string{resourceID}, []cloudBadge{badge}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(resourceID + "/" + badge.Key)
+	return resourceCloudBadgeRead(ctx, d, m)
+}
+
+func resourceCloudBadgeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	resourceID := d.Get("cloud_resource_id").(string)
+	key := d.Get("key").(string)
+	badges, err := listResourceBadges(ctx, c, resourceID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, badge := range badges[resourceID] {
+		if badge.Key == key {
+			d.Set("value", badge.Value)
+			return diags
+		}
+	}
+
+	tflog.Warn(ctx, fmt.Sprintf("Badge %s no longer exists on cloud %s, removing it from state", key, resourceID))
+	d.SetId("")
+	return diags
+}
+
+func resourceCloudBadgeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_badge", "updated")
+	}
+
+	// Creating a badge with an existing key overwrites its value
+	badge := cloudBadge{Key: d.Get("key").(string), Value: d.Get("value").(string)}
+	if err := setResourceBadges(ctx, c, cloudBadgesCreatePath, []string{d.Get("cloud_resource_id").(string)}, []cloudBadge{badge}); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceCloudBadgeRead(ctx, d, m)
+}
+
+func resourceCloudBadgeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_badge", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	badge := cloudBadge{Key: d.Get("key").(string), Value: d.Get("value").(string)}
+	if err := setResourceBadges(ctx, c, cloudBadgesDeletePath, []string{d.Get("cloud_resource_id").(string)}, []cloudBadge{badge}); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,196 @@
+package insightcloudsec
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	cloudBadgesListPath   = "/v2/public/badges/list"
+	cloudBadgesCreatePath = "/v2/public/badges/create"
+	cloudBadgesUpdatePath = "/v2/public/badges/update"
+	cloudBadgesDeletePath = "/v2/public/badges/delete"
+)
+
+// resourceBadges are the badges of a single resource as returned by the API
+type resourceBadges struct {
+	ResourceID string       `json:"resource_id"`
+	Badges     []cloudBadge `json:"badges"`
+}
+
+func resourceCloudBadges() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudBadgesCreate,
+		ReadContext:   resourceCloudBadgesRead,
+		UpdateContext: resourceCloudBadgesUpdate,
+		DeleteContext: resourceCloudBadgesDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudBadgesImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"cloud_resource_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The resource_ids of the clouds whose badges are managed",
+			},
+			"badges": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The badges of the clouds.  Any other badge on the clouds is removed",
+			},
+		},
+	}
+}
+
+// cloudBadgesID joins the sorted resource IDs, which contain colons, with commas
+func cloudBadgesID(resourceIDs []string) string {
+	sorted := append([]string{}, resourceIDs...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func resourceCloudBadgesImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("cloud_resource_ids", strings.Split(d.Id(), ","))
+	return []*schema.ResourceData{d}, nil
+}
+
+// listResourceBadges returns the badges of the given resources keyed by their
+// resource ID
+func listResourceBadges(ctx context.Context, c *apiClient, resourceIDs ...string) (map[string][]cloudBadge, error) {
+	var result struct {
+		Resources []resourceBadges `json:"resources"`
+	}
+	body := map[string]interface{}{"target_resource_ids": resourceIDs}
+	err := c.request(ctx, http.MethodPost, cloudBadgesListPath, body, &result)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to list badges: %s", err)
+	}
+
+	badges := make(map[string][]cloudBadge, len(result.Resources))
+	for _, r := range result.Resources {
+		badges[r.ResourceID] = r.Badges
+	}
+	return badges, nil
+}
+
+// setResourceBadges changes the badges of the given resources.  The update path
+// replaces every badge, the create path adds or overwrites the given badges and
+// the delete path removes them.
+func setResourceBadges(ctx context.Context, c *apiClient, path string, resourceIDs []string, badges []cloudBadge) error {
+	tflog.Debug(ctx, fmt.Sprintf("Badges to Set on %v with %s: %v", resourceIDs, path, badges))
+	body := map[string]interface{}{
+		"target_resource_ids": resourceIDs,
+		"badges":              badges,
+	}
+	err := c.request(ctx, http.MethodPost, path, body, nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] Unable to set badges on %s: %s", strings.Join(resourceIDs, ", "), err)
+	}
+	return nil
+}
+
+// flattenCloudBadges returns the badges to keep in state.  Those are the
+// configured badges when every cloud has exactly them, otherwise the badges of
+// the first cloud that differs, so that any drift shows up as a diff.
+func flattenCloudBadges(resourceIDs []string, badges map[string][]cloudBadge, configured map[string]interface{}) map[string]interface{} {
+	sorted := append([]string{}, resourceIDs...)
+	sort.Strings(sorted)
+
+	for _, resourceID := range sorted {
+		actual := flattenBadgeMap(badges[resourceID])
+		if !reflect.DeepEqual(actual, configured) {
+			return actual
+		}
+	}
+	return configured
+}
+
+func resourceCloudBadgesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_badges", "created")
+	}
+
+	resourceIDs := setToList(d.Get("cloud_resource_ids").(*schema.Set))
+	badges := expandBadgeMap(d.Get("badges").(map[string]interface{}))
+	if err := setResourceBadges(ctx, c, cloudBadgesUpdatePath, resourceIDs, badges); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(cloudBadgesID(resourceIDs))
+	return resourceCloudBadgesRead(ctx, d, m)
+}
+
+func resourceCloudBadgesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	resourceIDs := setToList(d.Get("cloud_resource_ids").(*schema.Set))
+	badges, err := listResourceBadges(ctx, c, resourceIDs...)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("badges", flattenCloudBadges(resourceIDs, badges, d.Get("badges").(map[string]interface{})))
+	return diags
+}
+
+func resourceCloudBadgesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_badges", "updated")
+	}
+
+	resourceIDs := setToList(d.Get("cloud_resource_ids").(*schema.Set))
+	badges := expandBadgeMap(d.Get("badges").(map[string]interface{}))
+	if err := setResourceBadges(ctx, c, cloudBadgesUpdatePath, resourceIDs, badges); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceCloudBadgesRead(ctx, d, m)
+}
+
+// resourceCloudBadgesDelete removes every badge from the clouds
+func resourceCloudBadgesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_badges", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	resourceIDs := setToList(d.Get("cloud_resource_ids").(*schema.Set))
+	if err := setResourceBadges(ctx, c, cloudBadgesUpdatePath, resourceIDs, []cloudBadge{}); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,64 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFlattenCloudBadges(t *testing.T) {
+	configured := map[string]interface{}{"env": "prod"}
+	resourceIDs := []string{"divvyorganizationservice:2", "divvyorganizationservice:1"}
+
+	inSync := map[string][]cloudBadge{
+		"divvyorganizationservice:1": {{Key: "env", Value: "prod"}},
+		"divvyorganizationservice:2": {{Key: "env", Value: "prod"}},
+	}
+	if got := flattenCloudBadges(resourceIDs, inSync, configured); got["env"] != "prod" || len(got) != 1 {
+		t.Errorf("expected the configured badges, got %v", got)
+	}
+
+	drifted := map[string][]cloudBadge{
+		"divvyorganizationservice:1": {{Key: "env", Value: "prod"}},
+		"divvyorganizationservice:2": {{Key: "env", Value: "prod"}, {Key: "owner", Value: "someone"}},
+	}
+	if got := flattenCloudBadges(resourceIDs, drifted, configured); got["owner"] != "someone" {
+		t.Errorf("expected the badges of the drifted cloud, got %v", got)
+	}
+}
+
+func TestResourceCloudBadge_ReadRemovesMissingBadge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"resources": []resourceBadges{{
+				ResourceID: "divvyorganizationservice:1",
+				Badges:     []cloudBadge{{Key: "env", Value: "dev"}},
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	for key, want := range map[string]string{"env": "dev", "team": ""} {
+		d := schema.TestResourceDataRaw(t, resourceCloudBadge().Schema, map[string]interface{}{
+			"cloud_resource_id": "divvyorganizationservice:1",
+			"key":               key,
+			"value":             "prod",
+		})
+		d.SetId("divvyorganizationservice:1/" + key)
+
+		if diags := resourceCloudBadgeRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if want == "" && d.Id() != "" {
+			t.Errorf("expected badge %s to be removed from state", key)
+		}
+		if want != "" && d.Get("value") != want {
+			t.Errorf("expected badge %s to have value %s, got %v", key, want, d.Get("value"))
+		}
+	}
+}
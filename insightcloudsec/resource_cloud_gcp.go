@@ -0,0 +1,120 @@
+package insightcloudsec
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	ics "github.com/gstotts/insightcloudsec"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// gcpServiceAccountKey is the key file downloaded for a GCP service account
+type gcpServiceAccountKey struct {
+	Type                    string `json:"type"`
+	ProjectID               string `json:"project_id"`
+	PrivateKeyID            string `json:"private_key_id"`
+	PrivateKey              string `json:"private_key"`
+	ClientEmail             string `json:"client_email"`
+	ClientID                string `json:"client_id"`
+	AuthURI                 string `json:"auth_uri"`
+	TokenURI                string `json:"token_uri"`
+	AuthProviderx509CertURL string `json:"auth_provider_x509_cert_url"`
+	Clientx509CertURL       string `json:"client_x509_cert_url"`
+}
+
+// parseGCPCredentialsJSON reads and checks a service account key file
+func parseGCPCredentialsJSON(raw string) (ics.GCPAccountApiCreds, error) {
+	var key gcpServiceAccountKey
+	if err := json.Unmarshal([]byte(raw), &key); err != nil {
+		return ics.GCPAccountApiCreds{}, fmt.Errorf("[ERROR] credentials_json is not valid JSON: %s", err)
+	}
+
+	if key.Type != "service_account" {
+		return ics.GCPAccountApiCreds{}, fmt.Errorf("[ERROR] credentials_json must be a service account key, got type %q", key.Type)
+	}
+
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"project_id", key.ProjectID},
+		{"private_key_id", key.PrivateKeyID},
+		{"private_key", key.PrivateKey},
+		{"client_email", key.ClientEmail},
+		{"client_id", key.ClientID},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return ics.GCPAccountApiCreds{}, fmt.Errorf("[ERROR] credentials_json is missing %s", strings.Join(missing, ", "))
+	}
+
+	if block, _ := pem.Decode([]byte(key.PrivateKey)); block == nil {
+		return ics.GCPAccountApiCreds{}, fmt.Errorf("[ERROR] The private_key in credentials_json is not PEM encoded")
+	}
+
+	return ics.GCPAccountApiCreds{
+		Type:                    key.Type,
+		ProjectID:               key.ProjectID,
+		PrivateKeyID:            key.PrivateKeyID,
+		PrivateKey:              key.PrivateKey,
+		ClientEmail:             key.ClientEmail,
+		ClientID:                key.ClientID,
+		AuthURI:                 key.AuthURI,
+		TokenURI:                key.TokenURI,
+		AuthProviderx509CertURL: key.AuthProviderx509CertURL,
+		Clientx509CertUrl:       key.Clientx509CertURL,
+	}, nil
+}
+
+func validateGCPCredentialsJSON(v interface{}, p cty.Path) diag.Diagnostics {
+	if _, err := parseGCPCredentialsJSON(v.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid Service Account Key",
+			Detail:        err.Error(),
+			AttributePath: p,
+		}}
+	}
+	return nil
+}
+
+// gcpCredentials returns the service account key from either credentials_json
+// or the api_credentials block
+func gcpCredentials(d *schema.ResourceData) (ics.GCPAccountApiCreds, error) {
+	if raw := d.Get("gcp.0.credentials_json").(string); raw != "" {
+		return parseGCPCredentialsJSON(raw)
+	}
+
+	creds, ok := d.Get("gcp.0.api_credentials").(*schema.Set)
+	if !ok || creds.Len() == 0 {
+		return ics.GCPAccountApiCreds{}, fmt.Errorf("[ERROR] One of credentials_json or api_credentials is required for GCE clouds")
+	}
+	return gcpCredentialsExpand(creds), nil
+}
+
+func gcpCredentialsExpand(credSet *schema.Set) (creds ics.GCPAccountApiCreds) {
+	if credSet.Len() == 0 {
+		return creds
+	}
+
+	o := credSet.List()[0].(map[string]interface{})
+	creds = ics.GCPAccountApiCreds{
+		Type:                    o["type"].(string),
+		ProjectID:               o["project_id"].(string),
+		PrivateKeyID:            o["private_key_id"].(string),
+		PrivateKey:              o["private_key"].(string),
+		AuthURI:                 o["auth_uri"].(string),
+		TokenURI:                o["token_uri"].(string),
+		ClientEmail:             o["client_email"].(string),
+		ClientID:                o["client_id"].(string),
+		Clientx509CertUrl:       o["client_x509_cert_url"].(string),
+		AuthProviderx509CertURL: o["auth_provider_x509_cert_url"].(string),
+	}
+
+	return creds
+}
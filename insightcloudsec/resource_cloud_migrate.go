@@ -0,0 +1,96 @@
+package insightcloudsec
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Attributes of the version 0 schema that moved into each provider block
+var cloudV0BlockAttributes = map[string][]string{
+	"aws":   {"account", "authentication_type", "role_arn", "session_name", "external_id", "duration", "api_key", "secret_key"},
+	"azure": {"tenant_id", "subscription_id", "app_id", "api_key"},
+	"gcp":   {"project", "api_credentials"},
+}
+
+// resourceCloudV0 is the flat schema used before the provider blocks were added.
+// Only the types are needed to decode existing state.
+func resourceCloudV0() *schema.Resource {
+	str := func() *schema.Schema { return &schema.Schema{Type: schema.TypeString, Optional: true} }
+
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":                {Type: schema.TypeString, Required: true},
+			"creation_time":       {Type: schema.TypeString, Computed: true},
+			"last_updated":        {Type: schema.TypeString, Optional: true, Computed: true},
+			"status":              {Type: schema.TypeString, Computed: true},
+			"resource_id":         {Type: schema.TypeString, Computed: true},
+			"group_resource_id":   {Type: schema.TypeString, Computed: true},
+			"org_resource_id":     {Type: schema.TypeString, Computed: true},
+			"strategy_id":         {Type: schema.TypeInt, Computed: true},
+			"cloud_type":          {Type: schema.TypeString, Required: true},
+			"tenant_id":           str(),
+			"app_id":              str(),
+			"subscription_id":     str(),
+			"api_key":             str(),
+			"account":             str(),
+			"authentication_type": str(),
+			"role_arn":            str(),
+			"secret_key":          str(),
+			"duration":            {Type: schema.TypeInt, Optional: true},
+			"external_id":         str(),
+			"session_name":        str(),
+			"api_credentials": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type":                        str(),
+						"project_id":                  str(),
+						"private_key_id":              str(),
+						"private_key":                 str(),
+						"client_email":                str(),
+						"client_id":                   str(),
+						"auth_uri":                    str(),
+						"token_uri":                   str(),
+						"auth_provider_x509_cert_url": str(),
+						"client_x509_cert_url":        str(),
+					},
+				},
+			},
+			"project": str(),
+		},
+	}
+}
+
+// resourceCloudStateUpgradeV0 moves the flat attributes of the cloud type into its
+// provider block and drops the attributes of the other cloud types.
+func resourceCloudStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	cloudType, _ := rawState["cloud_type"].(string)
+	block := CLOUD_PARTITIONS[cloudType].Block
+
+	values := map[string]interface{}{}
+	for name, attrs := range cloudV0BlockAttributes {
+		for _, attr := range attrs {
+			if v, ok := rawState[attr]; ok && name == block && v != nil {
+				values[attr] = v
+			}
+		}
+	}
+	for _, attrs := range cloudV0BlockAttributes {
+		for _, attr := range attrs {
+			delete(rawState, attr)
+		}
+	}
+
+	if block == "" {
+		return rawState, nil
+	}
+	rawState[block] = []interface{}{values}
+
+	return rawState, nil
+}
@@ -0,0 +1,90 @@
+package insightcloudsec
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestResourceCloudStateUpgradeV0(t *testing.T) {
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		expected map[string]interface{}
+	}{
+		"aws": {
+			raw: map[string]interface{}{
+				"name":                "prod",
+				"cloud_type":          "AWS_GOV",
+				"account":             "123412341234",
+				"authentication_type": "assume_role",
+				"role_arn":            "arn:aws-us-gov:iam::123412341234:role/ICS",
+				"duration":            3600,
+				"api_key":             "key",
+				"tenant_id":           "",
+				"api_credentials":     []interface{}{},
+			},
+			expected: map[string]interface{}{
+				"name":       "prod",
+				"cloud_type": "AWS_GOV",
+				"aws": []interface{}{map[string]interface{}{
+					"account":             "123412341234",
+					"authentication_type": "assume_role",
+					"role_arn":            "arn:aws-us-gov:iam::123412341234:role/ICS",
+					"duration":            3600,
+					"api_key":             "key",
+				}},
+			},
+		},
+		"azure": {
+			raw: map[string]interface{}{
+				"cloud_type":      "AZURE_ARM",
+				"tenant_id":       "tenant",
+				"subscription_id": "sub",
+				"app_id":          "app",
+				"api_key":         "secret",
+				"account":         "",
+			},
+			expected: map[string]interface{}{
+				"cloud_type": "AZURE_ARM",
+				"azure": []interface{}{map[string]interface{}{
+					"tenant_id":       "tenant",
+					"subscription_id": "sub",
+					"app_id":          "app",
+					"api_key":         "secret",
+				}},
+			},
+		},
+		"gcp": {
+			raw: map[string]interface{}{
+				"cloud_type":      "GCE",
+				"project":         "my-project",
+				"api_credentials": []interface{}{map[string]interface{}{"client_id": "1"}},
+			},
+			expected: map[string]interface{}{
+				"cloud_type": "GCE",
+				"gcp": []interface{}{map[string]interface{}{
+					"project":         "my-project",
+					"api_credentials": []interface{}{map[string]interface{}{"client_id": "1"}},
+				}},
+			},
+		},
+	}
+
+	v0 := resourceCloudV0().Schema
+	for name, tc := range cases {
+		// The raw states must only hold attributes the released version 0 had
+		for attr := range tc.raw {
+			if _, ok := v0[attr]; !ok {
+				t.Fatalf("%s: %s is not part of the version 0 schema", name, attr)
+			}
+		}
+
+		got, err := resourceCloudStateUpgradeV0(context.Background(), tc.raw, nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected\n%#v\ngot\n%#v", name, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package insightcloudsec
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	ics "github.com/gstotts/insightcloudsec"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	ociTenancyPattern     = regexp.MustCompile(`^ocid1\.tenancy\.[a-z0-9-]+\.[a-z0-9-]*\.[a-z0-9]+$`)
+	ociUserPattern        = regexp.MustCompile(`^ocid1\.user\.[a-z0-9-]+\.[a-z0-9-]*\.[a-z0-9]+$`)
+	ociFingerprintPattern = regexp.MustCompile(`^([0-9a-f]{2}:){15}[0-9a-f]{2}$`)
+	ociRegionPattern      = regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d+$`)
+)
+
+// ociCloudParameters are the creation parameters for OCI clouds, which the ics
+// client does not wrap.  Empty fields are left unchanged on update.
+type ociCloudParameters struct {
+	CloudType   string `json:"cloud_type"`
+	Name        string `json:"name"`
+	TenancyID   string `json:"tenancy_id,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	PrivateKey  string `json:"private_key,omitempty"`
+	Region      string `json:"home_region,omitempty"`
+}
+
+func addOCICloud(ctx context.Context, c *apiClient, d *schema.ResourceData) (ics.Cloud, error) {
+	var cloud ics.Cloud
+	params := ociCloudParameters{
+		CloudType:   d.Get("cloud_type").(string),
+		Name:        d.Get("name").(string),
+		TenancyID:   d.Get("oci.0.tenancy_ocid").(string),
+		UserID:      d.Get("oci.0.user_ocid").(string),
+		Fingerprint: d.Get("oci.0.fingerprint").(string),
+		PrivateKey:  d.Get("oci.0.private_key").(string),
+		Region:      d.Get("oci.0.region").(string),
+	}
+
+	err := c.request(ctx, http.MethodPost, cloudAddPath, map[string]interface{}{"creation_params": params}, &cloud)
+	return cloud, err
+}
+
+// updateOCICloud sends the name and only the credential fields that changed
+func updateOCICloud(ctx context.Context, c *apiClient, d *schema.ResourceData) error {
+	params := ociCloudParameters{
+		CloudType: d.Get("cloud_type").(string),
+		Name:      d.Get("name").(string),
+		TenancyID: d.Get("oci.0.tenancy_ocid").(string),
+	}
+	if d.HasChange("oci.0.user_ocid") {
+		params.UserID = d.Get("oci.0.user_ocid").(string)
+	}
+	if d.HasChange("oci.0.fingerprint") {
+		params.Fingerprint = d.Get("oci.0.fingerprint").(string)
+	}
+	if d.HasChange("oci.0.private_key") {
+		params.PrivateKey = d.Get("oci.0.private_key").(string)
+	}
+	if d.HasChange("oci.0.region") {
+		params.Region = d.Get("oci.0.region").(string)
+	}
+
+	return c.request(ctx, http.MethodPost, cloudUpdatePath(d), map[string]interface{}{"creation_params": params}, nil)
+}
@@ -0,0 +1,542 @@
+package insightcloudsec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	cloudOrganizationAddPath = "/v2/prototype/cloud/organization/add"
+	cloudOrganizationPath    = "/v2/prototype/cloud/organization/%s"
+)
+
+var (
+	// The per-provider blocks of an organization, exactly one of which is set
+	CLOUD_ORGANIZATION_BLOCKS = []string{"aws", "azure", "gcp"}
+
+	// Cloud types that can be onboarded as an organization
+	CLOUD_ORGANIZATION_TYPES = []string{"AWS", "AWS_CHINA", "AWS_GOV", "AZURE_ARM", "AZURE_ARM_CHINA", "AZURE_ARM_GOV", "GCE"}
+
+	// The non-secret attributes of each block and their creation parameters
+	CLOUD_ORGANIZATION_PARAMS = map[string]map[string]string{
+		"aws": {
+			"management_account":  "account_id",
+			"authentication_type": "authentication_type",
+			"role_arn":            "role_arn",
+			"member_role_name":    "member_role_name",
+			"session_name":        "session_name",
+			"external_id":         "external_id",
+		},
+		"azure": {
+			"tenant_id":           "tenant_id",
+			"management_group_id": "management_group_id",
+			"app_id":              "app_id",
+		},
+		"gcp": {
+			"organization_id": "organization_id",
+		},
+	}
+)
+
+// cloudOrganization is an organization as sent to and returned by the API
+type cloudOrganization struct {
+	ID             int                       `json:"id,omitempty"`
+	ResourceID     string                    `json:"resource_id,omitempty"`
+	Name           string                    `json:"name"`
+	CloudType      string                    `json:"cloud_type"`
+	Status         string                    `json:"status,omitempty"`
+	AutoAdd        bool                      `json:"auto_add"`
+	Include        []string                  `json:"include_units"`
+	Exclude        []string                  `json:"exclude_units"`
+	NameTemplate   string                    `json:"nickname_template,omitempty"`
+	Badges         []cloudBadge              `json:"badges"`
+	CreationParams map[string]interface{}    `json:"creation_params,omitempty"`
+	Clouds         []cloudOrganizationMember `json:"clouds,omitempty"`
+}
+
+type cloudOrganizationMember struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	AccountID  string `json:"account_id"`
+	ResourceID string `json:"resource_id"`
+	Status     string `json:"status"`
+}
+
+type cloudBadge struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func resourceCloudOrganization() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudOrganizationCreate,
+		ReadContext:   resourceCloudOrganizationRead,
+		UpdateContext: resourceCloudOrganizationUpdate,
+		DeleteContext: resourceCloudOrganizationDelete,
+		CustomizeDiff: customdiff.All(
+			validateCloudOrganizationBlock,
+		),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the organization for display in InsightCloudSec",
+			},
+			"cloud_type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(CLOUD_ORGANIZATION_TYPES, false)),
+				Description:      "The type of the member clouds.  Supported Options: " + strings.Join(CLOUD_ORGANIZATION_TYPES, ", "),
+			},
+			"aws": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_ORGANIZATION_BLOCKS,
+				Description:  "The AWS Organizations management account and the role assumed in each member account",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"management_account": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`^\d{12}$`), "must be a 12 digit AWS account number")),
+							Description:      "The account number of the organization's management account",
+						},
+						"authentication_type": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"assume_role", "instance_assume_role"}, false)),
+							Description:      "The authentication type for the organization.  Supported Options: assume_role or instance_assume_role",
+						},
+						"role_arn": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`^arn:[a-z-]+:iam::\d{12}:role/`), "must be the ARN of an IAM role")),
+							Description:      "The ARN of the role to assume in the management account",
+						},
+						"member_role_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The name of the role to assume in each member account.  Defaults to the name of the role in role_arn",
+						},
+						"session_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "A name to give the session for accessing the accounts.  This name will be used when logging with CloudTrail",
+						},
+						"external_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "An optional unique identifier to include as part of the assume role handshake",
+						},
+						"api_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The access key ID used with assume_role authentication",
+						},
+						"secret_key": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Sensitive:    true,
+							RequiredWith: []string{"aws.0.api_key"},
+							Description:  "The secret access key used with assume_role authentication",
+						},
+					},
+				},
+			},
+			"azure": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_ORGANIZATION_BLOCKS,
+				Description:  "The Azure tenant or management group and the service principal used to harvest its subscriptions",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tenant_id": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsUUID),
+							Description:      "The tenant id of the organization",
+						},
+						"management_group_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "A management group to onboard instead of the whole tenant",
+						},
+						"app_id": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsUUID),
+							Description:      "The application id of the service principal",
+						},
+						"api_key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "The client secret of the service principal",
+						},
+					},
+				},
+			},
+			"gcp": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: CLOUD_ORGANIZATION_BLOCKS,
+				Description:  "The GCP organization and the service account used to harvest its projects",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"organization_id": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`^\d+$`), "must be a numeric GCP organization ID")),
+							Description:      "The numeric ID of the organization",
+						},
+						"credentials_json": {
+							Type:             schema.TypeString,
+							Required:         true,
+							Sensitive:        true,
+							ValidateDiagFunc: validateGCPCredentialsJSON,
+							Description:      "The contents of the key file of the service account used to harvest the organization, for example from file()",
+						},
+					},
+				},
+			},
+			"auto_add": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether accounts, subscriptions or projects added to the organization later are onboarded automatically",
+			},
+			"include": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The OUs, management groups or folders to onboard.  All are onboarded when empty",
+			},
+			"exclude": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The OUs, management groups or folders to skip",
+			},
+			"name_template": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`\{(account_id|account_name)\}`), "must contain {account_id} or {account_name}")),
+				Description:      "The template for the names of member clouds, using {account_id} and {account_name}",
+			},
+			"badges": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The badges to apply to each member cloud when it is onboarded",
+			},
+			"resource_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The resource_id provided by the console for the organization",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the organization",
+			},
+			"member_clouds": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The member clouds discovered in the organization so far",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"account_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateCloudOrganizationBlock checks that the provider block matches the cloud type
+func validateCloudOrganizationBlock(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	cloudType := d.Get("cloud_type").(string)
+	partition, ok := CLOUD_PARTITIONS[cloudType]
+	if !ok {
+		return nil
+	}
+
+	for _, block := range CLOUD_ORGANIZATION_BLOCKS {
+		if len(d.Get(block).([]interface{})) > 0 && block != partition.Block {
+			return fmt.Errorf("[ERROR] %s organizations are configured with the %s block, not %s", cloudType, partition.Block, block)
+		}
+	}
+
+	if d.NewValueKnown("aws.0.role_arn") {
+		roleArn, _ := d.Get("aws.0.role_arn").(string)
+		return validatePartitionValues(cloudType, roleArn)
+	}
+	return nil
+}
+
+// expandCloudOrganization builds the organization to send to the API.  Secrets
+// are only included when creating or when they have changed.
+func expandCloudOrganization(d *schema.ResourceData, create bool) (cloudOrganization, error) {
+	org := cloudOrganization{
+		Name:         d.Get("name").(string),
+		CloudType:    d.Get("cloud_type").(string),
+		AutoAdd:      d.Get("auto_add").(bool),
+		Include:      setToList(d.Get("include").(*schema.Set)),
+		Exclude:      setToList(d.Get("exclude").(*schema.Set)),
+		NameTemplate: d.Get("name_template").(string),
+		Badges:       expandBadgeMap(d.Get("badges").(map[string]interface{})),
+	}
+
+	changed := func(key string) bool {
+		return create || d.HasChange(key)
+	}
+
+	block := CLOUD_PARTITIONS[org.CloudType].Block
+	params := map[string]interface{}{}
+	for attr, param := range CLOUD_ORGANIZATION_PARAMS[block] {
+		params[param] = d.Get(fmt.Sprintf("%s.0.%s", block, attr)).(string)
+	}
+
+	switch block {
+	case "aws":
+		if changed("aws.0.api_key") {
+			params["api_key"] = d.Get("aws.0.api_key").(string)
+		}
+		if changed("aws.0.secret_key") {
+			params["secret_key"] = d.Get("aws.0.secret_key").(string)
+		}
+
+	case "azure":
+		if changed("azure.0.api_key") {
+			params["api_key"] = d.Get("azure.0.api_key").(string)
+		}
+
+	case "gcp":
+		if changed("gcp.0.credentials_json") {
+			creds, err := parseGCPCredentialsJSON(d.Get("gcp.0.credentials_json").(string))
+			if err != nil {
+				return org, err
+			}
+			params["gcp_auth"] = creds
+		}
+	}
+	org.CreationParams = params
+
+	return org, nil
+}
+
+// flattenCloudOrganization sets the non-secret attributes returned by the API
+func flattenCloudOrganization(d *schema.ResourceData, org cloudOrganization) {
+	d.Set("name", org.Name)
+	d.Set("cloud_type", org.CloudType)
+	d.Set("auto_add", org.AutoAdd)
+	d.Set("include", org.Include)
+	d.Set("exclude", org.Exclude)
+	d.Set("name_template", org.NameTemplate)
+	d.Set("badges", flattenBadgeMap(org.Badges))
+	d.Set("resource_id", org.ResourceID)
+	d.Set("status", org.Status)
+
+	// The API returns the creation parameters without secrets
+	values := map[string]interface{}{}
+	for attr, param := range CLOUD_ORGANIZATION_PARAMS[CLOUD_PARTITIONS[org.CloudType].Block] {
+		if v, ok := org.CreationParams[param]; ok && v != nil {
+			values[attr] = fmt.Sprint(v)
+		}
+	}
+	if block := CLOUD_PARTITIONS[org.CloudType].Block; block != "" && len(values) > 0 {
+		setCloudBlock(d, block, values)
+	}
+
+	members := make([]interface{}, 0, len(org.Clouds))
+	for _, cloud := range org.Clouds {
+		members = append(members, map[string]interface{}{
+			"id":          cloud.ID,
+			"name":        cloud.Name,
+			"account_id":  cloud.AccountID,
+			"resource_id": cloud.ResourceID,
+			"status":      cloud.Status,
+		})
+	}
+	d.Set("member_clouds", members)
+}
+
+func resourceCloudOrganizationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_organization", "created")
+	}
+	var diags diag.Diagnostics
+
+	org, err := expandCloudOrganization(d, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var created cloudOrganization
+	err = c.request(ctx, http.MethodPost, cloudOrganizationAddPath, org, &created)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Error Adding Cloud Organization",
+			Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
+				fmt.Sprintf("An error was returned when attempting to add the organization %s to InsightCloudSec.", org.Name),
+				"This could be the result of credentials that cannot list the accounts, subscriptions or projects of the organization.",
+				"Error from API:", err),
+		})
+		return diags
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Cloud Organization Returned from API: \n%v", created))
+	if created.ResourceID == "" {
+		return diag.FromErr(fmt.Errorf("[ERROR] InsightCloudSec did not return a resource ID for the organization %s", org.Name))
+	}
+
+	d.SetId(created.ResourceID)
+	return resourceCloudOrganizationRead(ctx, d, m)
+}
+
+func resourceCloudOrganizationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	var org cloudOrganization
+	err = c.request(ctx, http.MethodGet, fmt.Sprintf(cloudOrganizationPath, d.Id()), nil, &org)
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			tflog.Warn(ctx, fmt.Sprintf("Cloud organization %s no longer exists, removing it from state", d.Id()))
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	flattenCloudOrganization(d, org)
+	return diags
+}
+
+func resourceCloudOrganizationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_organization", "updated")
+	}
+	var diags diag.Diagnostics
+
+	org, err := expandCloudOrganization(d, false)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf(cloudOrganizationPath, d.Id())+"/update", org, nil)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Error Updating Cloud Organization",
+			Detail: fmt.Sprintf("%s\n\n%s\n%s",
+				fmt.Sprintf("An error was returned when attempting to update the organization %s in InsightCloudSec.", org.Name),
+				"Error from API:", err),
+		})
+		return diags
+	}
+
+	return resourceCloudOrganizationRead(ctx, d, m)
+}
+
+func resourceCloudOrganizationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_organization", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	err = c.request(ctx, http.MethodDelete, fmt.Sprintf(cloudOrganizationPath, d.Id()), nil, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func setToList(s *schema.Set) []string {
+	return interfaceToList(s.List())
+}
+
+func expandBadgeMap(m map[string]interface{}) []cloudBadge {
+	badges := make([]cloudBadge, 0, len(m))
+	for k, v := range m {
+		badges = append(badges, cloudBadge{Key: k, Value: v.(string)})
+	}
+	return badges
+}
+
+func flattenBadgeMap(badges []cloudBadge) map[string]interface{} {
+	m := make(map[string]interface{}, len(badges))
+	for _, badge := range badges {
+		m[badge.Key] = badge.Value
+	}
+	return m
+}
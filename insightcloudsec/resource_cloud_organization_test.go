@@ -0,0 +1,134 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestExpandCloudOrganization(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCloudOrganization().Schema, map[string]interface{}{
+		"name":       "Production",
+		"cloud_type": "AWS",
+		"aws": []interface{}{map[string]interface{}{
+			"management_account":  "123412341234",
+			"authentication_type": "assume_role",
+			"role_arn":            "arn:aws:iam::123412341234:role/ICS",
+			"api_key":             "key",
+		}},
+		"exclude": []interface{}{"ou-sandbox"},
+		"badges":  map[string]interface{}{"env": "prod"},
+	})
+
+	org, err := expandCloudOrganization(d, true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !org.AutoAdd || len(org.Exclude) != 1 || org.Exclude[0] != "ou-sandbox" {
+		t.Errorf("unexpected organization settings: %#v", org)
+	}
+	if org.CreationParams["account_id"] != "123412341234" || org.CreationParams["api_key"] != "key" {
+		t.Errorf("unexpected creation parameters: %#v", org.CreationParams)
+	}
+	if len(org.Badges) != 1 || org.Badges[0] != (cloudBadge{Key: "env", Value: "prod"}) {
+		t.Errorf("unexpected badges: %#v", org.Badges)
+	}
+}
+
+func TestResourceCloudOrganizationRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/prototype/cloud/organization/divvyorganization:7":
+			json.NewEncoder(w).Encode(cloudOrganization{
+				ResourceID:     "divvyorganization:7",
+				Name:           "Production",
+				CloudType:      "AWS",
+				AutoAdd:        true,
+				CreationParams: map[string]interface{}{"account_id": "123412341234", "role_arn": "arn:aws:iam::123412341234:role/ICS"},
+				Clouds: []cloudOrganizationMember{
+					{ID: 1, Name: "prod-a", AccountID: "111111111111", ResourceID: "divvyorganizationservice:1", Status: "DEFAULT"},
+					{ID: 2, Name: "prod-b", AccountID: "222222222222", ResourceID: "divvyorganizationservice:2", Status: "DEFAULT"},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := resourceCloudOrganization().TestResourceData()
+	d.SetId("divvyorganization:7")
+
+	if diags := resourceCloudOrganizationRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if d.Get("member_clouds.#") != 2 || d.Get("member_clouds.1.account_id") != "222222222222" {
+		t.Errorf("unexpected member clouds: %v", d.Get("member_clouds"))
+	}
+	if d.Get("aws.0.management_account") != "123412341234" {
+		t.Errorf("expected the management account to be read, got %v", d.Get("aws"))
+	}
+
+	d.SetId("divvyorganization:8")
+	if diags := resourceCloudOrganizationRead(context.Background(), d, c); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected a missing organization to be removed from state, got %v", diags)
+	}
+}
+
+func TestResourceCloudOrganizationCreate_ServerDefaults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org := cloudOrganization{ResourceID: "divvyorganization:7"}
+		if r.Method == http.MethodGet {
+			// The API fills in the optional role settings that were not sent
+			org = cloudOrganization{
+				ResourceID: "divvyorganization:7",
+				Name:       "Production",
+				CloudType:  "AWS",
+				AutoAdd:    true,
+				Include:    []string{},
+				Exclude:    []string{},
+				CreationParams: map[string]interface{}{
+					"account_id":          "123412341234",
+					"authentication_type": "assume_role",
+					"role_arn":            "arn:aws:iam::123412341234:role/ICS",
+					"member_role_name":    "ICS",
+					"session_name":        "InsightCloudSec",
+					"external_id":         "divvy-external",
+				},
+			}
+		}
+		json.NewEncoder(w).Encode(org)
+	}))
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"name":       "Production",
+		"cloud_type": "AWS",
+		"aws": []interface{}{map[string]interface{}{
+			"management_account":  "123412341234",
+			"authentication_type": "assume_role",
+			"role_arn":            "arn:aws:iam::123412341234:role/ICS",
+		}},
+	}
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceCloudOrganization().Schema, raw)
+	if diags := resourceCloudOrganizationCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	sm := schema.InternalMap(resourceCloudOrganization().Schema)
+	diff, err := sm.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), nil, nil, true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected an empty plan after create, got %v", diff.Attributes)
+	}
+}
@@ -0,0 +1,126 @@
+package insightcloudsec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// cloudPartition describes the partition specific values of a cloud type
+type cloudPartition struct {
+	// Family is the commercial cloud type whose attributes the partition uses
+	Family string
+	// Block is the provider block that holds the cloud's account and credentials
+	Block         string
+	ARNPartition  string
+	AuthorityHost string
+}
+
+var CLOUD_PARTITIONS = map[string]cloudPartition{
+	"AWS":             {Family: "AWS", Block: "aws", ARNPartition: "aws"},
+	"AWS_GOV":         {Family: "AWS", Block: "aws", ARNPartition: "aws-us-gov"},
+	"AWS_CHINA":       {Family: "AWS", Block: "aws", ARNPartition: "aws-cn"},
+	"AZURE_ARM":       {Family: "AZURE_ARM", Block: "azure", AuthorityHost: "login.microsoftonline.com"},
+	"AZURE_ARM_GOV":   {Family: "AZURE_ARM", Block: "azure", AuthorityHost: "login.microsoftonline.us"},
+	"AZURE_ARM_CHINA": {Family: "AZURE_ARM", Block: "azure", AuthorityHost: "login.chinacloudapi.cn"},
+	"GCE":             {Family: "GCE", Block: "gcp"},
+	"OCI":             {Family: "OCI", Block: "oci"},
+	"ALICLOUD":        {Family: "ALICLOUD", Block: "alibaba"},
+}
+
+// cloudTypes returns the supported cloud type IDs in a stable order
+func cloudTypes() []string {
+	types := make([]string, 0, len(CLOUD_PARTITIONS))
+	for cloudType := range CLOUD_PARTITIONS {
+		types = append(types, cloudType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// cloudFamily maps a partitioned cloud type such as AWS_GOV onto AWS
+func cloudFamily(cloudType string) string {
+	if partition, ok := CLOUD_PARTITIONS[cloudType]; ok {
+		return partition.Family
+	}
+	return cloudType
+}
+
+// validatePartitionValues checks that a role ARN belongs to the partition of the
+// cloud type.  Empty values are not checked.
+func validatePartitionValues(cloudType, roleArn string) error {
+	partition, ok := CLOUD_PARTITIONS[cloudType]
+	if !ok {
+		return nil
+	}
+
+	if roleArn != "" && partition.ARNPartition != "" {
+		prefix := fmt.Sprintf("arn:%s:", partition.ARNPartition)
+		if !strings.HasPrefix(roleArn, prefix) {
+			return fmt.Errorf("[ERROR] role_arn must start with %s for %s clouds, got %s", prefix, cloudType, roleArn)
+		}
+	}
+
+	return nil
+}
+
+func validateCloudPartition(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	cloudType := d.Get("cloud_type").(string)
+	roleArn := ""
+	if d.NewValueKnown("aws.0.role_arn") {
+		roleArn, _ = d.Get("aws.0.role_arn").(string)
+	}
+	return validatePartitionValues(cloudType, roleArn)
+}
+
+// validateCloudBlock checks that the provider block matches the cloud type
+func validateCloudBlock(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	cloudType := d.Get("cloud_type").(string)
+	partition, ok := CLOUD_PARTITIONS[cloudType]
+	if !ok {
+		return nil
+	}
+
+	for _, block := range CLOUD_BLOCKS {
+		if len(d.Get(block).([]interface{})) > 0 && block != partition.Block {
+			return fmt.Errorf("[ERROR] %s clouds are configured with the %s block, not %s", cloudType, partition.Block, block)
+		}
+	}
+	return nil
+}
+
+// validateCloudTypeAvailable cross-checks the cloud type against the types the
+// instance reports, as listed by the insightcloudsec_cloud_types data source.
+func validateCloudTypeAvailable(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && !d.HasChange("cloud_type") {
+		return nil
+	}
+
+	meta, ok := m.(*apiClient)
+	if !ok || !d.NewValueKnown("cloud_type") {
+		return nil
+	}
+	c, err := meta.withContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctypes, err := c.Clouds.ListTypes()
+	if err != nil {
+		return err
+	}
+
+	cloudType := d.Get("cloud_type").(string)
+	available := make([]string, 0, len(ctypes.CloudTypes))
+	for _, t := range ctypes.CloudTypes {
+		if t.ID == cloudType {
+			return nil
+		}
+		available = append(available, t.ID)
+	}
+
+	return fmt.Errorf("[ERROR] Cloud type %s is not available on this InsightCloudSec instance.  Available types: %s", cloudType, strings.Join(available, ", "))
+}
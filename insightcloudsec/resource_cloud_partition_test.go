@@ -0,0 +1,34 @@
+package insightcloudsec
+
+import "testing"
+
+func TestValidatePartitionValues(t *testing.T) {
+	cases := []struct {
+		cloudType string
+		roleArn   string
+		valid     bool
+	}{
+		{"AWS", "arn:aws:iam::123412341234:role/ICS", true},
+		{"AWS", "arn:aws-us-gov:iam::123412341234:role/ICS", false},
+		{"AWS_GOV", "arn:aws-us-gov:iam::123412341234:role/ICS", true},
+		{"AWS_GOV", "arn:aws:iam::123412341234:role/ICS", false},
+		{"AWS_CHINA", "arn:aws-cn:iam::123412341234:role/ICS", true},
+		{"AZURE_ARM_GOV", "", true},
+		{"GCE", "", true},
+	}
+
+	for _, tc := range cases {
+		err := validatePartitionValues(tc.cloudType, tc.roleArn)
+		if (err == nil) != tc.valid {
+			t.Errorf("%s %q: expected valid to be %t, got %v", tc.cloudType, tc.roleArn, tc.valid, err)
+		}
+	}
+}
+
+func TestCloudFamily(t *testing.T) {
+	for cloudType, family := range map[string]string{"AWS_GOV": "AWS", "AWS_CHINA": "AWS", "AZURE_ARM_GOV": "AZURE_ARM", "GCE": "GCE"} {
+		if got := cloudFamily(cloudType); got != family {
+			t.Errorf("expected %s to be in the %s family, got %s", cloudType, family, got)
+		}
+	}
+}
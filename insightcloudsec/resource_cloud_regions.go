@@ -0,0 +1,310 @@
+package insightcloudsec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	cloudRegionsPath        = "/v2/public/cloud/%s/regions/list"
+	cloudRegionsEnablePath  = "/v2/public/cloud/%s/regions/enable"
+	cloudRegionsDisablePath = "/v2/public/cloud/%s/regions/disable"
+
+	REGION_ENABLED  = "ENABLED"
+	REGION_DISABLED = "DISABLED"
+
+	REGIONS_MODE_ENABLED  = "enabled"
+	REGIONS_MODE_DISABLED = "disabled"
+)
+
+type cloudRegion struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func resourceCloudRegions() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCloudRegionsCreate,
+		ReadContext:   resourceCloudRegionsRead,
+		UpdateContext: resourceCloudRegionsUpdate,
+		DeleteContext: resourceCloudRegionsDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudRegionsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"cloud_resource_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The resource_id of the cloud whose regions are managed",
+			},
+			"enabled_regions": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"enabled_regions", "disabled_regions"},
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Description:  "The only regions to harvest.  Every other region of the cloud is disabled",
+			},
+			"disabled_regions": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"enabled_regions", "disabled_regions"},
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Description:  "The regions not to harvest.  Every other region of the cloud is enabled",
+			},
+			"available_regions": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Every region of the cloud, enabled or not",
+			},
+			"mode": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Whether the regions are managed through enabled_regions or disabled_regions",
+			},
+		},
+	}
+}
+
+// The ID is <cloud resource_id>, optionally followed by /enabled to list the
+// enabled rather than the disabled regions
+func resourceCloudRegionsImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	resourceID, mode, found := strings.Cut(d.Id(), "/")
+	if !found {
+		mode = REGIONS_MODE_DISABLED
+	}
+	if resourceID == "" || (mode != REGIONS_MODE_ENABLED && mode != REGIONS_MODE_DISABLED) {
+		return nil, fmt.Errorf("[ERROR] Invalid import ID %q, expected <cloud resource_id> or <cloud resource_id>/enabled", d.Id())
+	}
+
+	d.SetId(resourceID)
+	d.Set("cloud_resource_id", resourceID)
+	d.Set("mode", mode)
+	return []*schema.ResourceData{d}, nil
+}
+
+// cloudRegionsMode reports whether enabled_regions or disabled_regions is used.
+// The configuration decides when there is one, as an empty enabled_regions
+// disables every region.  Refreshes and imports use the mode in state.
+func cloudRegionsMode(d *schema.ResourceData) string {
+	if config := d.GetRawConfig(); !config.IsNull() {
+		if config.GetAttr("enabled_regions").IsNull() {
+			return REGIONS_MODE_DISABLED
+		}
+		return REGIONS_MODE_ENABLED
+	}
+	if mode, ok := d.GetOk("mode"); ok {
+		return mode.(string)
+	}
+	if _, ok := d.GetOk("enabled_regions"); ok {
+		return REGIONS_MODE_ENABLED
+	}
+	return REGIONS_MODE_DISABLED
+}
+
+func listCloudRegions(ctx context.Context, c *apiClient, resourceID string) ([]cloudRegion, error) {
+	var result struct {
+		Regions []cloudRegion `json:"regions"`
+	}
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf(cloudRegionsPath, resourceID), nil, &result)
+	return result.Regions, err
+}
+
+// cloudRegionChanges works out which regions to enable and disable so that only
+// the enabled regions, or all but the disabled regions, are harvested.
+func cloudRegionChanges(regions []cloudRegion, enabled, disabled []string) (enable, disable []string, err error) {
+	available := make(map[string]string, len(regions))
+	for _, region := range regions {
+		available[region.Name] = strings.ToUpper(region.Status)
+	}
+
+	configured := enabled
+	if enabled == nil {
+		configured = disabled
+	}
+	want := make(map[string]bool, len(configured))
+	var unknown []string
+	for _, name := range configured {
+		if _, ok := available[name]; !ok {
+			unknown = append(unknown, name)
+		}
+		want[name] = true
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, nil, fmt.Errorf("[ERROR] The cloud does not have the regions %s", strings.Join(unknown, ", "))
+	}
+
+	for name, status := range available {
+		shouldEnable := want[name]
+		if enabled == nil {
+			shouldEnable = !want[name]
+		}
+
+		if shouldEnable && status == REGION_DISABLED {
+			enable = append(enable, name)
+		}
+		if !shouldEnable && status != REGION_DISABLED {
+			disable = append(disable, name)
+		}
+	}
+	sort.Strings(enable)
+	sort.Strings(disable)
+	return enable, disable, nil
+}
+
+func applyCloudRegions(ctx context.Context, c *apiClient, d *schema.ResourceData) error {
+	resourceID := d.Get("cloud_resource_id").(string)
+	regions, err := listCloudRegions(ctx, c, resourceID)
+	if err != nil {
+		return err
+	}
+
+	var enabled, disabled []string
+	if cloudRegionsMode(d) == REGIONS_MODE_ENABLED {
+		enabled = setToList(d.Get("enabled_regions").(*schema.Set))
+		if enabled == nil {
+			enabled = []string{}
+		}
+	} else {
+		disabled = setToList(d.Get("disabled_regions").(*schema.Set))
+	}
+
+	enable, disable, err := cloudRegionChanges(regions, enabled, disabled)
+	if err != nil {
+		return err
+	}
+	return setCloudRegionStatus(ctx, c, resourceID, enable, disable)
+}
+
+func setCloudRegionStatus(ctx context.Context, c *apiClient, resourceID string, enable, disable []string) error {
+	tflog.Debug(ctx, fmt.Sprintf("Regions of %s to enable: %v, to disable: %v", resourceID, enable, disable))
+
+	if len(enable) > 0 {
+		err := c.request(ctx, http.MethodPost, fmt.Sprintf(cloudRegionsEnablePath, resourceID), map[string]interface{}{"region_names": enable}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if len(disable) > 0 {
+		err := c.request(ctx, http.MethodPost, fmt.Sprintf(cloudRegionsDisablePath, resourceID), map[string]interface{}{"region_names": disable}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceCloudRegionsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_regions", "created")
+	}
+
+	if err := applyCloudRegions(ctx, c, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("cloud_resource_id").(string))
+	return resourceCloudRegionsRead(ctx, d, m)
+}
+
+func resourceCloudRegionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	regions, err := listCloudRegions(ctx, c, d.Id())
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			tflog.Warn(ctx, fmt.Sprintf("Cloud %s no longer exists, removing its regions from state", d.Id()))
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	var available, enabled, disabled []string
+	for _, region := range regions {
+		available = append(available, region.Name)
+		if strings.EqualFold(region.Status, REGION_DISABLED) {
+			disabled = append(disabled, region.Name)
+		} else {
+			enabled = append(enabled, region.Name)
+		}
+	}
+
+	mode := cloudRegionsMode(d)
+	d.Set("cloud_resource_id", d.Id())
+	d.Set("available_regions", available)
+	d.Set("mode", mode)
+	if mode == REGIONS_MODE_ENABLED {
+		d.Set("enabled_regions", enabled)
+	} else {
+		d.Set("disabled_regions", disabled)
+	}
+	return diags
+}
+
+func resourceCloudRegionsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_regions", "updated")
+	}
+
+	if err := applyCloudRegions(ctx, c, d); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceCloudRegionsRead(ctx, d, m)
+}
+
+// resourceCloudRegionsDelete enables every region again, which is the default
+// for a new cloud
+func resourceCloudRegionsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_cloud_regions", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	regions, err := listCloudRegions(ctx, c, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	enable, _, err := cloudRegionChanges(regions, nil, []string{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := setCloudRegionStatus(ctx, c, d.Id(), enable, nil); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,127 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCloudRegionChanges(t *testing.T) {
+	regions := []cloudRegion{
+		{Name: "us-east-1", Status: "ENABLED"},
+		{Name: "us-west-2", Status: "ENABLED"},
+		{Name: "eu-west-1", Status: "DISABLED"},
+		{Name: "ap-south-1", Status: "ENABLED"},
+	}
+
+	cases := map[string]struct {
+		enabled, disabled []string
+		enable, disable   []string
+	}{
+		"enabled only":     {enabled: []string{"us-east-1", "eu-west-1"}, enable: []string{"eu-west-1"}, disable: []string{"ap-south-1", "us-west-2"}},
+		"disabled only":    {disabled: []string{"ap-south-1"}, enable: []string{"eu-west-1"}, disable: []string{"ap-south-1"}},
+		"already in place": {disabled: []string{"eu-west-1"}},
+	}
+
+	for name, tc := range cases {
+		enable, disable, err := cloudRegionChanges(regions, tc.enabled, tc.disabled)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if !reflect.DeepEqual(enable, tc.enable) || !reflect.DeepEqual(disable, tc.disable) {
+			t.Errorf("%s: expected to enable %v and disable %v, got %v and %v", name, tc.enable, tc.disable, enable, disable)
+		}
+	}
+
+	if _, _, err := cloudRegionChanges(regions, nil, []string{"mars-north-1"}); err == nil {
+		t.Error("expected an error for a region the cloud does not have")
+	}
+}
+
+func TestResourceCloudRegionsRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/public/cloud/divvyorganizationservice:42/regions/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"regions": []cloudRegion{
+			{Name: "us-east-1", Status: "ENABLED"},
+			{Name: "eu-west-1", Status: "DISABLED"},
+		}})
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := resourceCloudRegions().TestResourceData()
+	d.SetId("divvyorganizationservice:42")
+
+	if diags := resourceCloudRegionsRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	disabled := d.Get("disabled_regions").(*schema.Set)
+	if disabled.Len() != 1 || !disabled.Contains("eu-west-1") || d.Get("available_regions").(*schema.Set).Len() != 2 {
+		t.Errorf("unexpected regions: disabled %v, available %v", disabled.List(), d.Get("available_regions"))
+	}
+}
+
+func TestResourceCloudRegionsMode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"regions": []cloudRegion{
+			{Name: "us-east-1", Status: "DISABLED"},
+			{Name: "eu-west-1", Status: "DISABLED"},
+		}})
+	}))
+	defer srv.Close()
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+
+	cases := map[string]struct {
+		importID string
+		raw      map[string]interface{}
+		mode     string
+		enabled  int
+		disabled int
+	}{
+		"all disabled":    {raw: map[string]interface{}{"enabled_regions": []interface{}{"us-east-1"}}, mode: REGIONS_MODE_ENABLED, enabled: 0, disabled: 0},
+		"import":          {importID: "divvyorganizationservice:42", mode: REGIONS_MODE_DISABLED, enabled: 0, disabled: 2},
+		"import enabled":  {importID: "divvyorganizationservice:42/enabled", mode: REGIONS_MODE_ENABLED, enabled: 0, disabled: 0},
+		"import disabled": {importID: "divvyorganizationservice:42/disabled", mode: REGIONS_MODE_DISABLED, enabled: 0, disabled: 2},
+	}
+
+	for name, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceCloudRegions().Schema, tc.raw)
+		if tc.importID != "" {
+			d.SetId(tc.importID)
+			if _, err := resourceCloudRegionsImport(context.Background(), d, c); err != nil {
+				t.Fatalf("%s: err: %s", name, err)
+			}
+		} else {
+			d.SetId("divvyorganizationservice:42")
+		}
+
+		// The mode has to survive a second refresh with every region disabled
+		for i := 0; i < 2; i++ {
+			if diags := resourceCloudRegionsRead(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("%s: unexpected diagnostics: %v", name, diags)
+			}
+		}
+
+		if d.Id() != "divvyorganizationservice:42" || d.Get("mode") != tc.mode {
+			t.Errorf("%s: expected cloud divvyorganizationservice:42 in %s mode, got %s in %v mode", name, tc.mode, d.Id(), d.Get("mode"))
+		}
+		enabled, disabled := d.Get("enabled_regions").(*schema.Set), d.Get("disabled_regions").(*schema.Set)
+		if enabled.Len() != tc.enabled || disabled.Len() != tc.disabled {
+			t.Errorf("%s: expected %d enabled and %d disabled regions, got %v and %v", name, tc.enabled, tc.disabled, enabled.List(), disabled.List())
+		}
+	}
+
+	d := resourceCloudRegions().TestResourceData()
+	d.SetId("divvyorganizationservice:42/all")
+	if _, err := resourceCloudRegionsImport(context.Background(), d, c); err == nil {
+		t.Error("expected an error for an unknown import mode")
+	}
+}
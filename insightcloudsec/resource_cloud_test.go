@@ -1,5 +1,222 @@
 package insightcloudsec
 
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ics "github.com/gstotts/insightcloudsec"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestFlattenCloudAccountSettings(t *testing.T) {
+	d := resourceCloud().TestResourceData()
+	d.Set("aws", []interface{}{map[string]interface{}{"api_key": "configured-secret"}})
+
+	flattenCloudAccountSettings(d, "AWS", cloudAccountSettings{
+		AccountID:          "123412341234",
+		AuthenticationType: "assume_role",
+		RoleArn:            "arn:aws:iam::123412341234:role/ChangedInConsole",
+		ExternalID:         "external",
+		SessionName:        "InsightCloudSec",
+		Duration:           3600,
+	})
+
+	expected := map[string]interface{}{
+		"aws.0.account":             "123412341234",
+		"aws.0.authentication_type": "assume_role",
+		"aws.0.role_arn":            "arn:aws:iam::123412341234:role/ChangedInConsole",
+		"aws.0.external_id":         "external",
+		"aws.0.session_name":        "InsightCloudSec",
+		"aws.0.duration":            3600,
+		"aws.0.api_key":             "configured-secret",
+	}
+	for k, v := range expected {
+		if got := d.Get(k); got != v {
+			t.Errorf("expected %s to be %v, got %v", k, v, got)
+		}
+	}
+
+	d = resourceCloud().TestResourceData()
+	flattenCloudAccountSettings(d, "AZURE_ARM_GOV", cloudAccountSettings{AccountID: "sub", TenantID: "tenant", AppID: "app"})
+	if d.Get("azure.0.subscription_id") != "sub" || d.Get("azure.0.tenant_id") != "tenant" || d.Get("azure.0.app_id") != "app" || d.Get("azure.0.authority_host") != "login.microsoftonline.us" {
+		t.Errorf("unexpected Azure settings: %v", d.Get("azure"))
+	}
+
+	d = resourceCloud().TestResourceData()
+	d.Set("oci", []interface{}{map[string]interface{}{"private_key": "configured-key"}})
+	flattenCloudAccountSettings(d, "OCI", cloudAccountSettings{AccountID: "ocid1.tenancy.oc1..aaaa", UserID: "ocid1.user.oc1..bbbb", Fingerprint: "aa:bb", Region: "us-ashburn-1"})
+	if d.Get("oci.0.tenancy_ocid") != "ocid1.tenancy.oc1..aaaa" || d.Get("oci.0.user_ocid") != "ocid1.user.oc1..bbbb" || d.Get("oci.0.region") != "us-ashburn-1" || d.Get("oci.0.private_key") != "configured-key" {
+		t.Errorf("unexpected OCI settings: %v", d.Get("oci"))
+	}
+
+	d = resourceCloud().TestResourceData()
+	flattenCloudAccountSettings(d, "ALICLOUD", cloudAccountSettings{AccessKeyID: "LTAI5t", RoleArn: "acs:ram::1234567890123456:role/ics"})
+	if d.Get("alibaba.0.access_key_id") != "LTAI5t" || d.Get("alibaba.0.ram_role_arn") != "acs:ram::1234567890123456:role/ics" {
+		t.Errorf("unexpected Alibaba settings: %v", d.Get("alibaba"))
+	}
+}
+
+func TestSetCloudHarvestingPaused(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body["resource_ids"]) != 1 || body["resource_ids"][0] != "divvyorganizationservice:42" {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		paths = append(paths, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	for _, paused := range []bool{true, false} {
+		if err := setCloudHarvestingPaused(context.Background(), c, "divvyorganizationservice:42", paused); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if len(paths) != 2 || paths[0] != cloudPausePath || paths[1] != cloudResumePath {
+		t.Errorf("expected a pause and then a resume, got %v", paths)
+	}
+}
+
+func TestUpdateAlibabaCloud(t *testing.T) {
+	var params map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		params = body["creation_params"]
+	}))
+	defer srv.Close()
+
+	// The plan removes ram_role_arn from a cloud that had one
+	state := &terraform.InstanceState{
+		ID: "7",
+		Attributes: map[string]string{
+			"name":                        "Alibaba",
+			"cloud_type":                  "ALICLOUD",
+			"resource_id":                 "divvyorganizationservice:7",
+			"alibaba.#":                   "1",
+			"alibaba.0.access_key_id":     "LTAI5t",
+			"alibaba.0.access_key_secret": "secret",
+			"alibaba.0.ram_role_arn":      "acs:ram::1234567890123456:role/ics",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":       "Alibaba",
+		"cloud_type": "ALICLOUD",
+		"alibaba":    []interface{}{map[string]interface{}{"access_key_id": "LTAI5t", "access_key_secret": "secret"}},
+	})
+	sm := schema.InternalMap(resourceCloud().Schema)
+	diff, err := sm.Diff(context.Background(), state, config, nil, nil, true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := sm.Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	if err := updateAlibabaCloud(context.Background(), c, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if v, ok := params["role_arn"]; !ok || v != "" {
+		t.Errorf("expected the removed role_arn to be sent empty, got %v", params)
+	}
+	if _, ok := params["access_key_id"]; ok {
+		t.Errorf("expected the unchanged access_key_id not to be sent, got %v", params)
+	}
+}
+
+func TestResourceCloudCreate_MissingID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceCloud().Schema, map[string]interface{}{
+		"name":       "Alibaba",
+		"cloud_type": "ALICLOUD",
+		"alibaba":    []interface{}{map[string]interface{}{"access_key_id": "LTAI5t", "access_key_secret": "secret"}},
+	})
+
+	diags := resourceCloudCreate(context.Background(), d, c)
+	if !diags.HasError() || d.Id() != "" {
+		t.Errorf("expected an error and no ID when the API returns no cloud ID, got %q and %v", d.Id(), diags)
+	}
+}
+
+// testCloudListServer serves the given clouds from the cloud list endpoint and
+// returns a client for it
+func testCloudListServer(t *testing.T, clouds []ics.Cloud) *apiClient {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/clouds/list") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(ics.CloudList{Clouds: clouds})
+	}))
+	t.Cleanup(srv.Close)
+
+	return &apiClient{
+		baseURL:    srv.URL,
+		apiKey:     "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxy",
+		httpClient: http.DefaultClient,
+	}
+}
+
+func TestResourceCloudImport(t *testing.T) {
+	c := testCloudListServer(t, []ics.Cloud{
+		{ID: 1, Name: "Production", CloudTypeID: "AWS", AccountID: "123456789012", ResourceID: "divvyorganizationservice:1"},
+		{ID: 2, Name: "Shared", CloudTypeID: "AWS", AccountID: "210987654321", ResourceID: "divvyorganizationservice:2"},
+		{ID: 3, Name: "Shared", CloudTypeID: "AZURE_ARM", AccountID: "210987654321", ResourceID: "divvyorganizationservice:3"},
+	})
+
+	cases := map[string]struct {
+		importID string
+		id       string
+		errorMsg string
+	}{
+		"numeric id":        {"1", "1", ""},
+		"name":              {"name:Production", "1", ""},
+		"account":           {"account:123456789012", "1", ""},
+		"resource id":       {"resource_id:divvyorganizationservice:3", "3", ""},
+		"unknown id":        {"99", "", "No cloud found with id"},
+		"unknown name":      {"name:Staging", "", "No cloud found with name"},
+		"duplicate name":    {"name:Shared", "", "2 clouds found with name"},
+		"duplicate account": {"account:210987654321", "", "2 clouds found with account"},
+		"unknown prefix":    {"tenant:abc", "", "Invalid import ID"},
+		"bare name":         {"Production", "", "Invalid import ID"},
+	}
+
+	for name, tc := range cases {
+		d := resourceCloud().TestResourceData()
+		d.SetId(tc.importID)
+
+		result, err := resourceCloudImport(context.Background(), d, c)
+		if tc.errorMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errorMsg) {
+				t.Errorf("%s: expected an error containing %q, got %v", name, tc.errorMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if result[0].Id() != tc.id {
+			t.Errorf("%s: expected cloud %s, got %s", name, tc.id, result[0].Id())
+		}
+	}
+}
+
 // func TestAccInsightCloudSec_Resource_Cloud(t *testing.T) {
 // 	rnd := generateRandomResourceName()
 // 	name := fmt.Sprintf("insightcloudsec_cloud.%s", rnd)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	ics "github.com/gstotts/insightcloudsec"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -18,6 +19,12 @@ func resourceInsight() *schema.Resource {
 		ReadContext:   resourceInsightRead,
 		UpdateContext: resourceInsightUpdate,
 		DeleteContext: resourceInsightDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -124,7 +131,13 @@ func resourceInsight() *schema.Resource {
 }
 
 func resourceInsightCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_custom_insight", "created")
+	}
 	var diags diag.Diagnostics
 
 	insight := prepareInsight(d)
@@ -148,7 +161,10 @@ func resourceInsightCreate(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceInsightRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	var diags diag.Diagnostics
 
 	id, _ := strconv.Atoi(d.Get("id").(string))
@@ -181,13 +197,19 @@ func resourceInsightRead(ctx context.Context, d *schema.ResourceData, m interfac
 }
 
 func resourceInsightUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_custom_insight", "updated")
+	}
 	var diags diag.Diagnostics
 
 	id, _ := strconv.Atoi(d.Get("id").(string))
 	insight := prepareInsight(d)
 	insight.ID = id
-	err := c.Insights.Edit(insight)
+	err = c.Insights.Edit(insight)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -195,11 +217,17 @@ func resourceInsightUpdate(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceInsightDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*ics.Client)
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_custom_insight", "deleted")
+	}
 	var diags diag.Diagnostics
 
 	id, _ := strconv.Atoi(d.Get("id").(string))
-	err := c.Insights.Delete(id)
+	err = c.Insights.Delete(id)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -0,0 +1,258 @@
+package insightcloudsec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	edhConfigurationPath = "/v2/public/eventdriven/config"
+
+	// There is a single event-driven harvesting configuration per organization
+	edhConfigurationID = "edh"
+)
+
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]*)?-[a-z]+-\d+$`)
+
+// edhConfiguration is the event-driven harvesting configuration as sent to and
+// returned by the API
+type edhConfiguration struct {
+	Enabled   bool          `json:"enabled"`
+	Consumers []edhConsumer `json:"consumers"`
+	Producers []string      `json:"producers"`
+}
+
+type edhConsumer struct {
+	ResourceID   string        `json:"resource_id"`
+	Regions      []string      `json:"regions"`
+	EventBusArns []edhEventBus `json:"event_bus_arns,omitempty"`
+}
+
+type edhEventBus struct {
+	Region string `json:"region"`
+	Arn    string `json:"arn"`
+}
+
+func resourceEDHConfiguration() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceEDHConfigurationCreate,
+		ReadContext:   resourceEDHConfigurationRead,
+		UpdateContext: resourceEDHConfigurationUpdate,
+		DeleteContext: resourceEDHConfigurationDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether event-driven harvesting is enabled",
+			},
+			"consumer": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "An AWS cloud that receives the CloudTrail events of the producers on an EventBridge bus",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cloud_resource_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The resource_id of the consumer cloud",
+						},
+						"regions": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Description: "The regions in which the consumer receives events",
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(awsRegionPattern, "must be an AWS region such as us-east-1")),
+							},
+						},
+					},
+				},
+			},
+			"producer_cloud_resource_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The resource_ids of the clouds whose events are sent to the consumers",
+			},
+			"event_bus_arns": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The EventBridge buses of the consumers, which the producers send their events to",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cloud_resource_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"arn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandEDHConfiguration(d *schema.ResourceData) edhConfiguration {
+	config := edhConfiguration{
+		Enabled:   d.Get("enabled").(bool),
+		Consumers: []edhConsumer{},
+		Producers: setToList(d.Get("producer_cloud_resource_ids").(*schema.Set)),
+	}
+
+	for _, item := range d.Get("consumer").(*schema.Set).List() {
+		consumer := item.(map[string]interface{})
+		config.Consumers = append(config.Consumers, edhConsumer{
+			ResourceID: consumer["cloud_resource_id"].(string),
+			Regions:    setToList(consumer["regions"].(*schema.Set)),
+		})
+	}
+	return config
+}
+
+func flattenEDHConfiguration(d *schema.ResourceData, config edhConfiguration) {
+	d.Set("enabled", config.Enabled)
+	d.Set("producer_cloud_resource_ids", config.Producers)
+
+	consumers := make([]interface{}, 0, len(config.Consumers))
+	buses := make([]interface{}, 0)
+	for _, consumer := range config.Consumers {
+		consumers = append(consumers, map[string]interface{}{
+			"cloud_resource_id": consumer.ResourceID,
+			"regions":           consumer.Regions,
+		})
+		for _, bus := range consumer.EventBusArns {
+			buses = append(buses, map[string]interface{}{
+				"cloud_resource_id": consumer.ResourceID,
+				"region":            bus.Region,
+				"arn":               bus.Arn,
+			})
+		}
+	}
+	d.Set("consumer", consumers)
+	d.Set("event_bus_arns", buses)
+}
+
+func putEDHConfiguration(ctx context.Context, c *apiClient, config edhConfiguration) error {
+	tflog.Debug(ctx, fmt.Sprintf("Event-Driven Harvesting Configuration to Set:\n%v\n", config))
+	err := c.request(ctx, http.MethodPost, edhConfigurationPath, config, nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] Unable to set the event-driven harvesting configuration: %s", err)
+	}
+	return nil
+}
+
+// edhConfigured reports whether event-driven harvesting has been set up, as the
+// API always returns a configuration
+func edhConfigured(config edhConfiguration) bool {
+	return config.Enabled || len(config.Consumers) > 0 || len(config.Producers) > 0
+}
+
+func resourceEDHConfigurationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_edh_configuration", "created")
+	}
+
+	// Creating would silently replace a configuration made in the console
+	var existing edhConfiguration
+	err = c.request(ctx, http.MethodGet, edhConfigurationPath, nil, &existing)
+	if err != nil {
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return diag.FromErr(err)
+		}
+	}
+	if edhConfigured(existing) {
+		return diag.FromErr(fmt.Errorf("[ERROR] An event-driven harvesting configuration already exists, import it instead with the ID %s", edhConfigurationID))
+	}
+
+	if err := putEDHConfiguration(ctx, c, expandEDHConfiguration(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(edhConfigurationID)
+	return resourceEDHConfigurationRead(ctx, d, m)
+}
+
+func resourceEDHConfigurationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	var config edhConfiguration
+	err = c.request(ctx, http.MethodGet, edhConfigurationPath, nil, &config)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(edhConfigurationID)
+	flattenEDHConfiguration(d, config)
+	return diags
+}
+
+func resourceEDHConfigurationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_edh_configuration", "updated")
+	}
+
+	if err := putEDHConfiguration(ctx, c, expandEDHConfiguration(d)); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceEDHConfigurationRead(ctx, d, m)
+}
+
+// resourceEDHConfigurationDelete disables event-driven harvesting and removes
+// every consumer and producer
+func resourceEDHConfigurationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_edh_configuration", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	if err := putEDHConfiguration(ctx, c, edhConfiguration{Consumers: []edhConsumer{}, Producers: []string{}}); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,82 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceEDHConfiguration_CreateAndRead(t *testing.T) {
+	var stored edhConfiguration
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != edhConfigurationPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&stored)
+		case http.MethodGet:
+			config := stored
+			for i, consumer := range config.Consumers {
+				for _, region := range consumer.Regions {
+					config.Consumers[i].EventBusArns = append(config.Consumers[i].EventBusArns, edhEventBus{
+						Region: region,
+						Arn:    "arn:aws:events:" + region + ":123412341234:event-bus/insightcloudsec",
+					})
+				}
+			}
+			json.NewEncoder(w).Encode(config)
+		}
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceEDHConfiguration().Schema, map[string]interface{}{
+		"consumer": []interface{}{map[string]interface{}{
+			"cloud_resource_id": "divvyorganizationservice:1",
+			"regions":           []interface{}{"us-east-1"},
+		}},
+		"producer_cloud_resource_ids": []interface{}{"divvyorganizationservice:2", "divvyorganizationservice:3"},
+	})
+
+	if diags := resourceEDHConfigurationCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !stored.Enabled || len(stored.Producers) != 2 || len(stored.Consumers) != 1 {
+		t.Errorf("unexpected configuration sent: %#v", stored)
+	}
+	if d.Id() != edhConfigurationID || d.Get("event_bus_arns.0.arn") != "arn:aws:events:us-east-1:123412341234:event-bus/insightcloudsec" {
+		t.Errorf("unexpected event bus ARNs: %v", d.Get("event_bus_arns"))
+	}
+}
+
+func TestResourceEDHConfiguration_CreateExisting(t *testing.T) {
+	posted := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = true
+			return
+		}
+		json.NewEncoder(w).Encode(edhConfiguration{Enabled: true, Producers: []string{"divvyorganizationservice:2"}})
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceEDHConfiguration().Schema, map[string]interface{}{
+		"producer_cloud_resource_ids": []interface{}{"divvyorganizationservice:3"},
+	})
+
+	diags := resourceEDHConfigurationCreate(context.Background(), d, c)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "import it instead") {
+		t.Errorf("expected an error asking to import the configuration, got %v", diags)
+	}
+	if posted {
+		t.Error("expected the existing configuration not to be replaced")
+	}
+}
@@ -0,0 +1,232 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	groupCreatePath      = "/v2/prototype/groups/create"
+	groupPath            = "/v2/prototype/group/%s"
+	groupUpdatePath      = "/v2/prototype/group/%s/update"
+	groupRolesAddPath    = "/v2/prototype/group/%s/roles/add"
+	groupRolesRemovePath = "/v2/prototype/group/%s/roles/remove"
+)
+
+// group is a user group as sent to and returned by the API
+type group struct {
+	ResourceID  string      `json:"resource_id,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Roles       []groupRole `json:"roles,omitempty"`
+	Users       []int       `json:"users,omitempty"`
+}
+
+// groupRole is a role of a group.  Depending on the version the API lists the
+// roles as resource IDs or as objects with the resource ID and name.
+type groupRole struct {
+	ResourceID string `json:"resource_id"`
+	Name       string `json:"name"`
+}
+
+func (r *groupRole) UnmarshalJSON(data []byte) error {
+	var resourceID string
+	if err := json.Unmarshal(data, &resourceID); err == nil {
+		r.ResourceID = resourceID
+		return nil
+	}
+
+	type role groupRole
+	return json.Unmarshal(data, (*role)(r))
+}
+
+func resourceGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupCreate,
+		ReadContext:   resourceGroupRead,
+		UpdateContext: resourceGroupUpdate,
+		DeleteContext: resourceGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the group",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the group",
+			},
+			"role_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The resource_ids of the roles granted to the members of the group",
+			},
+			"resource_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The resource_id provided by the console for the group",
+			},
+		},
+	}
+}
+
+func getGroup(ctx context.Context, c *apiClient, resourceID string) (group, error) {
+	var g group
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf(groupPath, resourceID), nil, &g)
+	return g, err
+}
+
+// setGroupMembers adds or removes the given members of a group using one of
+// the paths that take the group's resource ID
+func setGroupMembers(ctx context.Context, c *apiClient, path, resourceID, field string, members interface{}) error {
+	tflog.Debug(ctx, fmt.Sprintf("Group %s %s to change with %s: %v", resourceID, field, path, members))
+	body := map[string]interface{}{field: members}
+	err := c.request(ctx, http.MethodPost, fmt.Sprintf(path, resourceID), body, nil)
+	if err != nil {
+		return fmt.Errorf("[ERROR] Unable to change the %s of group %s: %s", field, resourceID, err)
+	}
+	return nil
+}
+
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_group", "created")
+	}
+
+	g := group{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}
+	var created group
+	err = c.request(ctx, http.MethodPost, groupCreatePath, g, &created)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Group Returned from API: %s", created.ResourceID))
+	if created.ResourceID == "" {
+		return diag.FromErr(fmt.Errorf("[ERROR] InsightCloudSec did not return a resource ID for the group %s", g.Name))
+	}
+	d.SetId(created.ResourceID)
+
+	if roles := setToList(d.Get("role_ids").(*schema.Set)); len(roles) > 0 {
+		if err := setGroupMembers(ctx, c, groupRolesAddPath, d.Id(), "role_resource_ids", roles); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceGroupRead(ctx, d, m)
+}
+
+func resourceGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	g, err := getGroup(ctx, c, d.Id())
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			tflog.Warn(ctx, fmt.Sprintf("Group %s no longer exists, removing it from state", d.Id()))
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("name", g.Name)
+	d.Set("description", g.Description)
+	// Roles listed by name only cannot be compared with role_ids, so the
+	// configured roles are kept rather than showing a diff on every plan
+	roles := make([]string, 0, len(g.Roles))
+	for _, role := range g.Roles {
+		if role.ResourceID == "" {
+			tflog.Warn(ctx, fmt.Sprintf("Group %s lists role %q without a resource ID, unable to check role_ids for drift", d.Id(), role.Name))
+			roles = nil
+			break
+		}
+		roles = append(roles, role.ResourceID)
+	}
+	if roles != nil {
+		d.Set("role_ids", roles)
+	}
+	d.Set("resource_id", g.ResourceID)
+	return diags
+}
+
+func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_group", "updated")
+	}
+
+	if d.HasChanges("name", "description") {
+		g := group{
+			Name:        d.Get("name").(string),
+			Description: d.Get("description").(string),
+		}
+		err = c.request(ctx, http.MethodPost, fmt.Sprintf(groupUpdatePath, d.Id()), g, nil)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("role_ids") {
+		o, n := d.GetChange("role_ids")
+		if remove := setToList(o.(*schema.Set).Difference(n.(*schema.Set))); len(remove) > 0 {
+			if err := setGroupMembers(ctx, c, groupRolesRemovePath, d.Id(), "role_resource_ids", remove); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if add := setToList(n.(*schema.Set).Difference(o.(*schema.Set))); len(add) > 0 {
+			if err := setGroupMembers(ctx, c, groupRolesAddPath, d.Id(), "role_resource_ids", add); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+	return resourceGroupRead(ctx, d, m)
+}
+
+func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_group", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	err = c.request(ctx, http.MethodDelete, fmt.Sprintf(groupPath, d.Id()), nil, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,171 @@
+package insightcloudsec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	groupUsersAddPath    = "/v2/prototype/group/%s/users/add"
+	groupUsersRemovePath = "/v2/prototype/group/%s/users/remove"
+)
+
+func resourceGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupMembershipCreate,
+		ReadContext:   resourceGroupMembershipRead,
+		UpdateContext: resourceGroupMembershipUpdate,
+		DeleteContext: resourceGroupMembershipDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGroupMembershipImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The resource_id of the group",
+			},
+			"user_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The IDs of the users to add to the group.  Other members of the group are left as they are",
+			},
+		},
+	}
+}
+
+// resourceGroupMembershipImport takes in every current member of the group, as
+// later refreshes only keep the users already in state
+func resourceGroupMembershipImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := getGroup(ctx, c, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Unable to read group %s: %s", d.Id(), err)
+	}
+
+	d.Set("group_id", d.Id())
+	d.Set("user_ids", g.Users)
+	return []*schema.ResourceData{d}, nil
+}
+
+func intSetToList(s *schema.Set) []int {
+	list := make([]int, 0, s.Len())
+	for _, v := range s.List() {
+		list = append(list, v.(int))
+	}
+	return list
+}
+
+func resourceGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_group_membership", "created")
+	}
+
+	groupID := d.Get("group_id").(string)
+	if err := setGroupMembers(ctx, c, groupUsersAddPath, groupID, "user_ids", intSetToList(d.Get("user_ids").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(groupID)
+	return resourceGroupMembershipRead(ctx, d, m)
+}
+
+// resourceGroupMembershipRead keeps the users in state that are still members of
+// the group.  Members added outside of Terraform are never taken in, even when
+// none of the managed users are left.
+func resourceGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	g, err := getGroup(ctx, c, d.Id())
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			tflog.Warn(ctx, fmt.Sprintf("Group %s no longer exists, removing its membership from state", d.Id()))
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	configured := d.Get("user_ids").(*schema.Set)
+	members := make([]interface{}, 0, len(g.Users))
+	for _, id := range g.Users {
+		if configured.Contains(id) {
+			members = append(members, id)
+		}
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Managed Members of Group %s: %v", d.Id(), members))
+
+	d.Set("group_id", d.Id())
+	d.Set("user_ids", members)
+	return diags
+}
+
+func resourceGroupMembershipUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_group_membership", "updated")
+	}
+
+	o, n := d.GetChange("user_ids")
+	if remove := intSetToList(o.(*schema.Set).Difference(n.(*schema.Set))); len(remove) > 0 {
+		if err := setGroupMembers(ctx, c, groupUsersRemovePath, d.Id(), "user_ids", remove); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if add := intSetToList(n.(*schema.Set).Difference(o.(*schema.Set))); len(add) > 0 {
+		if err := setGroupMembers(ctx, c, groupUsersAddPath, d.Id(), "user_ids", add); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceGroupMembershipRead(ctx, d, m)
+}
+
+// resourceGroupMembershipDelete removes only the configured users from the group
+func resourceGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_group_membership", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	if err := setGroupMembers(ctx, c, groupUsersRemovePath, d.Id(), "user_ids", intSetToList(d.Get("user_ids").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,154 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceGroupMembership_OnlyManagesConfiguredUsers(t *testing.T) {
+	members := map[int]bool{1: true}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			UserIDs []int `json:"user_ids"`
+		}
+		switch r.URL.Path {
+		case "/v2/prototype/group/divvygroup:7/users/add":
+			json.NewDecoder(r.Body).Decode(&body)
+			for _, id := range body.UserIDs {
+				members[id] = true
+			}
+		case "/v2/prototype/group/divvygroup:7/users/remove":
+			json.NewDecoder(r.Body).Decode(&body)
+			for _, id := range body.UserIDs {
+				delete(members, id)
+			}
+		case "/v2/prototype/group/divvygroup:7":
+			g := group{ResourceID: "divvygroup:7", Name: "Platform"}
+			for id := range members {
+				g.Users = append(g.Users, id)
+			}
+			json.NewEncoder(w).Encode(g)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceGroupMembership().Schema, map[string]interface{}{
+		"group_id": "divvygroup:7",
+		"user_ids": []interface{}{2, 3},
+	})
+
+	if diags := resourceGroupMembershipCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := intSetToList(d.Get("user_ids").(*schema.Set)); len(got) != 2 {
+		t.Errorf("expected only the configured users in state, got %v", got)
+	}
+
+	if diags := resourceGroupMembershipDelete(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	var remaining []int
+	for id := range members {
+		remaining = append(remaining, id)
+	}
+	sort.Ints(remaining)
+	if len(remaining) != 1 || remaining[0] != 1 {
+		t.Errorf("expected the existing member to be kept, got %v", remaining)
+	}
+}
+
+func TestResourceGroupRead_Roles(t *testing.T) {
+	cases := map[string]struct {
+		roles    string
+		expected []string
+	}{
+		"resource ids": {`["divvyrole:1:3", "divvyrole:1:4"]`, []string{"divvyrole:1:3", "divvyrole:1:4"}},
+		"objects":      {`[{"resource_id": "divvyrole:1:3", "name": "Auditors"}]`, []string{"divvyrole:1:3"}},
+		"names only":   {`[{"name": "Auditors"}]`, []string{"divvyrole:1:9"}},
+	}
+
+	for name, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"resource_id": "divvygroup:7", "name": "Platform", "roles": ` + tc.roles + `}`))
+		}))
+
+		c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+		d := schema.TestResourceDataRaw(t, resourceGroup().Schema, map[string]interface{}{
+			"name":     "Platform",
+			"role_ids": []interface{}{"divvyrole:1:9"},
+		})
+		d.SetId("divvygroup:7")
+
+		diags := resourceGroupRead(context.Background(), d, c)
+		srv.Close()
+		if diags.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", name, diags)
+		}
+
+		got := setToList(d.Get("role_ids").(*schema.Set))
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("%s: expected roles %v, got %v", name, tc.expected, got)
+		}
+	}
+}
+
+func TestResourceGroupCreate_MissingResourceID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "Platform"}`))
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceGroup().Schema, map[string]interface{}{"name": "Platform"})
+
+	diags := resourceGroupCreate(context.Background(), d, c)
+	if !diags.HasError() || d.Id() != "" {
+		t.Errorf("expected an error and no ID when the API returns no resource ID, got %q and %v", d.Id(), diags)
+	}
+}
+
+func TestResourceGroupMembership_ImportAndRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(group{ResourceID: "divvygroup:7", Name: "Platform", Users: []int{7, 8, 9}})
+	}))
+	defer srv.Close()
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+
+	// Every managed user was removed outside of Terraform, so state is empty
+	d := resourceGroupMembership().TestResourceData()
+	d.SetId("divvygroup:7")
+	d.Set("user_ids", []interface{}{})
+	for i := 0; i < 2; i++ {
+		if diags := resourceGroupMembershipRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+	}
+	if got := intSetToList(d.Get("user_ids").(*schema.Set)); len(got) != 0 {
+		t.Errorf("expected refresh not to take in unmanaged members, got %v", got)
+	}
+
+	d = resourceGroupMembership().TestResourceData()
+	d.SetId("divvygroup:7")
+	if _, err := resourceGroupMembershipImport(context.Background(), d, c); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diags := resourceGroupMembershipRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	got := intSetToList(d.Get("user_ids").(*schema.Set))
+	sort.Ints(got)
+	if len(got) != 3 || got[0] != 7 || got[2] != 9 || d.Get("group_id") != "divvygroup:7" {
+		t.Errorf("expected import to take in every member, got %v", got)
+	}
+}
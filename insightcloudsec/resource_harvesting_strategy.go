@@ -0,0 +1,228 @@
+package insightcloudsec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	harvestingStrategyCreatePath = "/v2/harvesting/strategy/create"
+	harvestingStrategyPath       = "/v2/harvesting/strategy/%s"
+	harvestingStrategyAssignPath = "/v2/harvesting/strategy/%d/assign"
+)
+
+// harvestingStrategy is a strategy as sent to and returned by the API.
+// Intervals are in minutes.
+type harvestingStrategy struct {
+	ID               int                  `json:"strategy_id,omitempty"`
+	Name             string               `json:"name"`
+	DefaultInterval  int                  `json:"default_interval"`
+	Intervals        []harvestingInterval `json:"resource_intervals"`
+	EnabledServices  []string             `json:"enabled_services"`
+	DisabledServices []string             `json:"disabled_services"`
+}
+
+type harvestingInterval struct {
+	ResourceType string `json:"resource_type"`
+	Interval     int    `json:"interval"`
+}
+
+func resourceHarvestingStrategy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceHarvestingStrategyCreate,
+		ReadContext:   resourceHarvestingStrategyRead,
+		UpdateContext: resourceHarvestingStrategyUpdate,
+		DeleteContext: resourceHarvestingStrategyDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the harvesting strategy",
+			},
+			"default_interval": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          60,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(5, 10080)),
+				Description:      "The harvest interval in minutes for resource types without a resource_interval",
+			},
+			"resource_interval": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The harvest interval of a single resource type",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The resource type, such as instance or storagecontainer",
+						},
+						"interval": {
+							Type:             schema.TypeInt,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(5, 10080)),
+							Description:      "The harvest interval in minutes",
+						},
+					},
+				},
+			},
+			"enabled_services": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"disabled_services"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Description:   "The only services to harvest.  All services are harvested when neither this nor disabled_services is set",
+			},
+			"disabled_services": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"enabled_services"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Description:   "The services not to harvest",
+			},
+		},
+	}
+}
+
+func expandHarvestingStrategy(d *schema.ResourceData) harvestingStrategy {
+	strategy := harvestingStrategy{
+		Name:             d.Get("name").(string),
+		DefaultInterval:  d.Get("default_interval").(int),
+		Intervals:        []harvestingInterval{},
+		EnabledServices:  setToList(d.Get("enabled_services").(*schema.Set)),
+		DisabledServices: setToList(d.Get("disabled_services").(*schema.Set)),
+	}
+
+	for _, item := range d.Get("resource_interval").(*schema.Set).List() {
+		i := item.(map[string]interface{})
+		strategy.Intervals = append(strategy.Intervals, harvestingInterval{
+			ResourceType: i["resource_type"].(string),
+			Interval:     i["interval"].(int),
+		})
+	}
+	return strategy
+}
+
+func flattenHarvestingStrategy(d *schema.ResourceData, strategy harvestingStrategy) {
+	d.Set("name", strategy.Name)
+	d.Set("default_interval", strategy.DefaultInterval)
+	d.Set("enabled_services", strategy.EnabledServices)
+	d.Set("disabled_services", strategy.DisabledServices)
+
+	intervals := make([]interface{}, 0, len(strategy.Intervals))
+	for _, i := range strategy.Intervals {
+		intervals = append(intervals, map[string]interface{}{
+			"resource_type": i.ResourceType,
+			"interval":      i.Interval,
+		})
+	}
+	d.Set("resource_interval", intervals)
+}
+
+// assignHarvestingStrategy sets the harvesting strategy of the given clouds
+func assignHarvestingStrategy(ctx context.Context, c *apiClient, strategyID int, resourceIDs ...string) error {
+	tflog.Debug(ctx, fmt.Sprintf("Assigning Harvesting Strategy %d to: %v", strategyID, resourceIDs))
+	body := map[string]interface{}{"resource_ids": resourceIDs}
+	return c.request(ctx, http.MethodPost, fmt.Sprintf(harvestingStrategyAssignPath, strategyID), body, nil)
+}
+
+func resourceHarvestingStrategyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_harvesting_strategy", "created")
+	}
+
+	strategy := expandHarvestingStrategy(d)
+	tflog.Debug(ctx, fmt.Sprintf("Harvesting Strategy to Create:\n%v\n", strategy))
+
+	var created harvestingStrategy
+	err = c.request(ctx, http.MethodPost, harvestingStrategyCreatePath, strategy, &created)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if created.ID == 0 {
+		return diag.FromErr(fmt.Errorf("[ERROR] InsightCloudSec did not return an ID for the harvesting strategy %s", strategy.Name))
+	}
+
+	d.SetId(strconv.Itoa(created.ID))
+	return resourceHarvestingStrategyRead(ctx, d, m)
+}
+
+func resourceHarvestingStrategyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	var strategy harvestingStrategy
+	err = c.request(ctx, http.MethodGet, fmt.Sprintf(harvestingStrategyPath, d.Id()), nil, &strategy)
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			tflog.Warn(ctx, fmt.Sprintf("Harvesting strategy %s no longer exists, removing it from state", d.Id()))
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	flattenHarvestingStrategy(d, strategy)
+	return diags
+}
+
+func resourceHarvestingStrategyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_harvesting_strategy", "updated")
+	}
+
+	strategy := expandHarvestingStrategy(d)
+	strategy.ID, _ = strconv.Atoi(d.Id())
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf(harvestingStrategyPath, d.Id())+"/update", strategy, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceHarvestingStrategyRead(ctx, d, m)
+}
+
+func resourceHarvestingStrategyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_harvesting_strategy", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	err = c.request(ctx, http.MethodDelete, fmt.Sprintf(harvestingStrategyPath, d.Id()), nil, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,73 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestExpandHarvestingStrategy(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceHarvestingStrategy().Schema, map[string]interface{}{
+		"name": "Development",
+		"resource_interval": []interface{}{
+			map[string]interface{}{"resource_type": "instance", "interval": 720},
+		},
+		"disabled_services": []interface{}{"sagemaker"},
+	})
+
+	expected := harvestingStrategy{
+		Name:             "Development",
+		DefaultInterval:  60,
+		Intervals:        []harvestingInterval{{ResourceType: "instance", Interval: 720}},
+		EnabledServices:  []string{},
+		DisabledServices: []string{"sagemaker"},
+	}
+	if got := expandHarvestingStrategy(d); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+
+	flattenHarvestingStrategy(d, harvestingStrategy{Name: "Development", DefaultInterval: 120, Intervals: []harvestingInterval{{ResourceType: "bucket", Interval: 30}}})
+	if d.Get("default_interval") != 120 || d.Get("resource_interval").(*schema.Set).Len() != 1 || d.Get("disabled_services").(*schema.Set).Len() != 0 {
+		t.Errorf("unexpected state after flatten: %v %v", d.Get("resource_interval"), d.Get("disabled_services"))
+	}
+}
+
+func TestAssignHarvestingStrategy(t *testing.T) {
+	var body map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/harvesting/strategy/3/assign" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	if err := assignHarvestingStrategy(context.Background(), c, 3, "divvyorganizationservice:42"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(body["resource_ids"]) != 1 || body["resource_ids"][0] != "divvyorganizationservice:42" {
+		t.Errorf("unexpected request body: %v", body)
+	}
+}
+
+func TestResourceHarvestingStrategyCreate_MissingID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceHarvestingStrategy().Schema, map[string]interface{}{"name": "Daily"})
+
+	diags := resourceHarvestingStrategyCreate(context.Background(), d, c)
+	if !diags.HasError() || d.Id() != "" {
+		t.Errorf("expected an error and no ID when the API returns no strategy ID, got %q and %v", d.Id(), diags)
+	}
+}
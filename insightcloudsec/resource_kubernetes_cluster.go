@@ -0,0 +1,269 @@
+package insightcloudsec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	kubernetesClusterAddPath = "/v2/kubernetes/cluster/add"
+	kubernetesClusterPath    = "/v2/kubernetes/cluster/%s"
+
+	KUBERNETES_HARVEST_AGENT   = "agent"
+	KUBERNETES_HARVEST_MANAGED = "managed"
+)
+
+var (
+	KUBERNETES_CLUSTER_TYPES = []string{"KUBERNETES", "EKS", "AKS", "GKE"}
+
+	// Cluster types that can be harvested through the API of their parent cloud
+	KUBERNETES_MANAGED_TYPES = map[string]bool{"EKS": true, "AKS": true, "GKE": true}
+)
+
+// kubernetesCluster is a cluster as sent to and returned by the API.  The agent
+// token is only returned when the cluster is added.
+type kubernetesCluster struct {
+	ResourceID            string            `json:"resource_id,omitempty"`
+	Name                  string            `json:"name"`
+	ClusterType           string            `json:"cluster_type"`
+	HarvestMethod         string            `json:"harvest_method"`
+	ClusterIdentifier     string            `json:"cluster_identifier,omitempty"`
+	ParentCloudResourceID string            `json:"parent_resource_id,omitempty"`
+	Status                string            `json:"status,omitempty"`
+	AgentToken            string            `json:"agent_token,omitempty"`
+	AgentAPIURL           string            `json:"agent_api_url,omitempty"`
+	ManifestValues        map[string]string `json:"manifest_values,omitempty"`
+}
+
+func resourceKubernetesCluster() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKubernetesClusterCreate,
+		ReadContext:   resourceKubernetesClusterRead,
+		UpdateContext: resourceKubernetesClusterUpdate,
+		DeleteContext: resourceKubernetesClusterDelete,
+		CustomizeDiff: validateKubernetesCluster,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the cluster for display in InsightCloudSec",
+			},
+			"cluster_type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(KUBERNETES_CLUSTER_TYPES, false)),
+				Description:      "The type of the cluster.  Supported Options: KUBERNETES, EKS, AKS or GKE",
+			},
+			"harvest_method": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          KUBERNETES_HARVEST_AGENT,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{KUBERNETES_HARVEST_AGENT, KUBERNETES_HARVEST_MANAGED}, false)),
+				Description:      "How the cluster is harvested.  Supported Options: agent (an agent installed in the cluster) or managed (through the API of the parent cloud, for EKS, AKS and GKE).  Defaults to agent",
+			},
+			"cluster_identifier": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The ARN of an EKS cluster, resource ID of an AKS cluster or full name of a GKE cluster.  Required when harvest_method is managed",
+			},
+			"parent_cloud_resource_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The resource_id of the cloud the cluster runs in.  Required when harvest_method is managed",
+			},
+			"resource_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The resource_id provided by the console for the cluster",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the cluster",
+			},
+			"agent_token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The token the in-cluster agent authenticates with",
+			},
+			"agent_api_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL the in-cluster agent reports to",
+			},
+			"manifest_values": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The values for the agent's Helm chart or manifest, including the agent token",
+			},
+		},
+	}
+}
+
+// validateKubernetesCluster checks the arguments required by the harvest method
+func validateKubernetesCluster(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	clusterType := d.Get("cluster_type").(string)
+	set := func(key string) bool {
+		return !d.NewValueKnown(key) || d.Get(key).(string) != ""
+	}
+
+	if d.Get("harvest_method").(string) != KUBERNETES_HARVEST_MANAGED {
+		if set("cluster_identifier") {
+			return fmt.Errorf("[ERROR] cluster_identifier can only be used when harvest_method is %q", KUBERNETES_HARVEST_MANAGED)
+		}
+		return nil
+	}
+
+	if !KUBERNETES_MANAGED_TYPES[clusterType] {
+		return fmt.Errorf("[ERROR] %s clusters must be harvested with an agent, only EKS, AKS and GKE clusters can be managed", clusterType)
+	}
+	for _, key := range []string{"cluster_identifier", "parent_cloud_resource_id"} {
+		if !set(key) {
+			return fmt.Errorf("[ERROR] %s is required when harvest_method is %q", key, KUBERNETES_HARVEST_MANAGED)
+		}
+	}
+	return nil
+}
+
+func expandKubernetesCluster(d *schema.ResourceData) kubernetesCluster {
+	return kubernetesCluster{
+		Name:                  d.Get("name").(string),
+		ClusterType:           d.Get("cluster_type").(string),
+		HarvestMethod:         d.Get("harvest_method").(string),
+		ClusterIdentifier:     d.Get("cluster_identifier").(string),
+		ParentCloudResourceID: d.Get("parent_cloud_resource_id").(string),
+	}
+}
+
+func flattenKubernetesCluster(d *schema.ResourceData, cluster kubernetesCluster) {
+	d.Set("name", cluster.Name)
+	d.Set("cluster_type", cluster.ClusterType)
+	d.Set("harvest_method", cluster.HarvestMethod)
+	d.Set("cluster_identifier", cluster.ClusterIdentifier)
+	d.Set("parent_cloud_resource_id", cluster.ParentCloudResourceID)
+	d.Set("resource_id", cluster.ResourceID)
+	d.Set("status", cluster.Status)
+	d.Set("agent_api_url", cluster.AgentAPIURL)
+
+	// The token is only returned when the cluster is added, so it is kept in state
+	if cluster.AgentToken != "" {
+		d.Set("agent_token", cluster.AgentToken)
+	}
+	if len(cluster.ManifestValues) > 0 {
+		d.Set("manifest_values", cluster.ManifestValues)
+	}
+}
+
+func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_kubernetes_cluster", "created")
+	}
+	var diags diag.Diagnostics
+
+	cluster := expandKubernetesCluster(d)
+	var created kubernetesCluster
+	err = c.request(ctx, http.MethodPost, kubernetesClusterAddPath, cluster, &created)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Error Adding Kubernetes Cluster",
+			Detail: fmt.Sprintf("%s\n%s\n\n%s\n%s",
+				fmt.Sprintf("An error was returned when attempting to add the cluster %s to InsightCloudSec.", cluster.Name),
+				"Managed clusters require a parent cloud whose credentials can describe the cluster.",
+				"Error from API:", err),
+		})
+		return diags
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Kubernetes Cluster Returned from API: %s", created.ResourceID))
+	if created.ResourceID == "" {
+		return diag.FromErr(fmt.Errorf("[ERROR] InsightCloudSec did not return a resource ID for the cluster %s", cluster.Name))
+	}
+
+	d.SetId(created.ResourceID)
+	flattenKubernetesCluster(d, created)
+	return resourceKubernetesClusterRead(ctx, d, m)
+}
+
+func resourceKubernetesClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+
+	var cluster kubernetesCluster
+	err = c.request(ctx, http.MethodGet, fmt.Sprintf(kubernetesClusterPath, d.Id()), nil, &cluster)
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			tflog.Warn(ctx, fmt.Sprintf("Kubernetes cluster %s no longer exists, removing it from state", d.Id()))
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	flattenKubernetesCluster(d, cluster)
+	return diags
+}
+
+func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_kubernetes_cluster", "updated")
+	}
+
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf(kubernetesClusterPath, d.Id())+"/update", expandKubernetesCluster(d), nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceKubernetesClusterRead(ctx, d, m)
+}
+
+func resourceKubernetesClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c, err := m.(*apiClient).withContext(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if c.readOnly {
+		return readOnlyError("insightcloudsec_kubernetes_cluster", "deleted")
+	}
+	var diags diag.Diagnostics
+
+	err = c.request(ctx, http.MethodDelete, fmt.Sprintf(kubernetesClusterPath, d.Id()), nil, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,66 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceKubernetesCluster_KeepsAgentToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cluster := kubernetesCluster{
+			ResourceID:    "kubernetescluster:9",
+			Name:          "prod-eks",
+			ClusterType:   "EKS",
+			HarvestMethod: KUBERNETES_HARVEST_AGENT,
+			Status:        "PENDING",
+			AgentAPIURL:   "https://ics.example.com/v2/kubernetes/agent",
+		}
+		switch r.URL.Path {
+		case kubernetesClusterAddPath:
+			cluster.AgentToken = "token"
+			cluster.ManifestValues = map[string]string{"token": "token", "clusterId": "9"}
+		case "/v2/kubernetes/cluster/kubernetescluster:9":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(cluster)
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceKubernetesCluster().Schema, map[string]interface{}{
+		"name":         "prod-eks",
+		"cluster_type": "EKS",
+	})
+
+	if diags := resourceKubernetesClusterCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if d.Id() != "kubernetescluster:9" || d.Get("agent_token") != "token" || d.Get("manifest_values.clusterId") != "9" {
+		t.Errorf("expected the agent token and manifest values to be kept after read, got %q %v", d.Get("agent_token"), d.Get("manifest_values"))
+	}
+}
+
+func TestResourceKubernetesClusterCreate_MissingResourceID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := &apiClient{baseURL: srv.URL, httpClient: http.DefaultClient}
+	d := schema.TestResourceDataRaw(t, resourceKubernetesCluster().Schema, map[string]interface{}{
+		"name":         "prod-eks",
+		"cluster_type": "EKS",
+	})
+
+	diags := resourceKubernetesClusterCreate(context.Background(), d, c)
+	if !diags.HasError() || d.Id() != "" {
+		t.Errorf("expected an error and no ID when the API returns no resource ID, got %q and %v", d.Id(), diags)
+	}
+}
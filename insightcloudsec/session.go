@@ -0,0 +1,217 @@
+package insightcloudsec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sessionLoginPath  = "/v2/public/user/login"
+	sessionLogoutPath = "/v2/public/user/logout"
+	sessionHeader     = "X-Auth-Token"
+
+	// Logging out at shutdown has to finish before go-plugin stops the process
+	sessionLogoutTimeout = 5 * time.Second
+)
+
+// openSessions tracks every session the provider has logged in with so they can
+// be logged out when the plugin shuts down.
+var openSessions struct {
+	sync.Mutex
+	list []*session
+}
+
+// Shutdown logs out of any username/password sessions opened by the provider.
+// It is called once the plugin has finished serving.
+func Shutdown() {
+	openSessions.Lock()
+	defer openSessions.Unlock()
+
+	for _, s := range openSessions.list {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionLogoutTimeout)
+		s.logout(ctx)
+		cancel()
+	}
+	openSessions.list = nil
+}
+
+// registerSession adds a logged in session to those logged out by Shutdown
+func registerSession(s *session) {
+	openSessions.Lock()
+	defer openSessions.Unlock()
+	openSessions.list = append(openSessions.list, s)
+}
+
+// session authenticates requests with a session token obtained by logging in
+// with a username and password, logging in again whenever the token expires.
+type session struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+func newSession(baseURL, username, password string, base http.RoundTripper) *session {
+	return &session{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+		client:   &http.Client{Transport: base},
+	}
+}
+
+func (s *session) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// Buffer the body so that the request can be replayed after logging in again
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := s.currentToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.send(req, body, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The session has expired, so log in again and retry once
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	token, err = s.refresh(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(req, body, token)
+}
+
+func (s *session) send(req *http.Request, body []byte, token string) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Header.Del("Api-Key")
+	r.Header.Set(sessionHeader, token)
+	if body != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+	}
+	return s.client.Transport.RoundTrip(r)
+}
+
+func (s *session) currentToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == "" {
+		if err := s.loginLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.token, nil
+}
+
+// refresh logs in again unless another request already replaced the expired token
+func (s *session) refresh(ctx context.Context, expired string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == expired {
+		if err := s.loginLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.token, nil
+}
+
+func (s *session) login(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loginLocked(ctx)
+}
+
+func (s *session) loginLocked(ctx context.Context) error {
+	payload, err := json.Marshal(map[string]string{
+		"username": s.username,
+		"password": s.password,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+sessionLoginPath, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("[ERROR] Unable to log in to InsightCloudSec: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("[ERROR] Unable to log in to InsightCloudSec as %s: %s", s.username, resp.Status)
+	}
+
+	var result struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("[ERROR] Unable to read login response from InsightCloudSec: %s", err)
+	}
+	if result.SessionID == "" {
+		return fmt.Errorf("[ERROR] InsightCloudSec did not return a session for %s", s.username)
+	}
+
+	s.token = result.SessionID
+	return nil
+}
+
+func (s *session) logout(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+sessionLogoutPath, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(sessionHeader, s.token)
+
+	// Logging out is sent once, as retries could outlast the plugin process
+	transport := s.client.Transport
+	if rt, ok := transport.(*retryTransport); ok {
+		transport = rt.base
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	s.token = ""
+	return nil
+}
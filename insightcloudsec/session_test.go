@@ -0,0 +1,148 @@
+package insightcloudsec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testSessionServer(t *testing.T, logins, logouts *int32) *httptest.Server {
+	var current atomic.Value
+	current.Store("")
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case sessionLoginPath:
+			var creds map[string]string
+			json.NewDecoder(r.Body).Decode(&creds)
+			if creds["username"] != "automation" || creds["password"] != "hunter2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			token := fmt.Sprintf("session-%d", atomic.AddInt32(logins, 1))
+			current.Store(token)
+			json.NewEncoder(w).Encode(map[string]string{"session_id": token})
+		case sessionLogoutPath:
+			atomic.AddInt32(logouts, 1)
+		default:
+			if r.Header.Get(sessionHeader) != current.Load().(string) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.Header.Get("Api-Key") != "" {
+				t.Errorf("expected Api-Key header to be removed")
+			}
+		}
+	}))
+}
+
+func TestSession_LogsInAndRefreshes(t *testing.T) {
+	var logins, logouts int32
+	srv := testSessionServer(t, &logins, &logouts)
+	defer srv.Close()
+
+	s := newSession(srv.URL+"/", "automation", "hunter2", http.DefaultTransport)
+	client := &http.Client{Transport: s}
+
+	resp, err := client.Get(srv.URL + "/v2/public/clouds/list")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || logins != 1 {
+		t.Fatalf("expected a single login and success, got %d logins and status %d", logins, resp.StatusCode)
+	}
+
+	// Expire the session so that the next request has to log in again
+	s.token = "expired"
+	resp, err = client.Get(srv.URL + "/v2/public/clouds/list")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || logins != 2 {
+		t.Fatalf("expected the session to be refreshed, got %d logins and status %d", logins, resp.StatusCode)
+	}
+
+	if err := s.logout(context.Background()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if logouts != 1 {
+		t.Fatalf("expected a logout, got %d", logouts)
+	}
+}
+
+func TestSession_BadCredentials(t *testing.T) {
+	var logins, logouts int32
+	srv := testSessionServer(t, &logins, &logouts)
+	defer srv.Close()
+
+	s := newSession(srv.URL, "automation", "wrong", http.DefaultTransport)
+	if err := s.login(context.Background()); err == nil {
+		t.Fatal("expected login with bad credentials to fail")
+	}
+}
+
+func TestSession_LogoutIsNotRetried(t *testing.T) {
+	var logouts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&logouts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	s := newSession(srv.URL, "automation", "hunter2", newRetryTransport(http.DefaultTransport, 3, time.Second, time.Second, 0))
+	s.token = "session-1"
+
+	start := time.Now()
+	s.logout(context.Background())
+	if logouts != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected a single logout without waiting for retries, got %d in %s", logouts, time.Since(start))
+	}
+}
+
+func TestProvider_FailedLoginIsNotRegistered(t *testing.T) {
+	var logins, logouts int32
+	srv := testSessionServer(t, &logins, &logouts)
+	defer srv.Close()
+
+	raw := map[string]interface{}{
+		"url":      srv.URL,
+		"username": "automation",
+		"password": "wrong",
+	}
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw)); !diags.HasError() {
+		t.Fatal("expected login with bad credentials to fail")
+	}
+
+	openSessions.Lock()
+	registered := len(openSessions.list)
+	openSessions.Unlock()
+	if registered != 0 {
+		t.Errorf("expected the failed session not to be registered, got %d sessions", registered)
+	}
+}
+
+func TestProvider_ConflictingAuthentication(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":      "https://example.com",
+		"apikey":   "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxy",
+		"username": "automation",
+		"password": "hunter2",
+	}
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw))
+	if !diags.HasError() || diags[0].Summary != "Conflicting Authentication Methods" {
+		t.Fatalf("expected conflicting authentication error, got %v", diags)
+	}
+}
@@ -16,4 +16,6 @@ func main() {
 			return insightcloudsec.Provider()
 		},
 	})
+
+	insightcloudsec.Shutdown()
 }